@@ -0,0 +1,146 @@
+package multihash
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	b58 "github.com/mr-tron/base58/base58"
+)
+
+// ErrInvalidCID is returned when a string or buffer is not a valid CID.
+var ErrInvalidCID = errors.New("invalid cid")
+
+// DagProtobuf is the codec of every CIDv0.
+const DagProtobuf = 0x70
+
+// FromCID extracts the multihash of a CID, along with the codec of the
+// content it addresses and the CID version. Both CIDv0 ("Qm…") and CIDv1
+// strings are accepted; the multibase encodings supported for CIDv1 are
+// base32 ("b"/"B", as in "bafy…"), base58btc ("z"), base16 ("f"/"F"),
+// base36 ("k"/"K") and unpadded base64 ("m") and base64url ("u").
+//
+// This only parses CIDs; github.com/ipfs/go-cid remains the package for
+// working with them.
+func FromCID(s string) (m Multihash, codec uint64, version int, err error) {
+	if len(s) == 46 && strings.HasPrefix(s, "Qm") {
+		b, err := b58.Decode(s)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("%w: %s", ErrInvalidCID, err)
+		}
+		return FromCIDBytes(b)
+	}
+	if len(s) < 2 {
+		return nil, 0, 0, fmt.Errorf("%w: %q is too short", ErrInvalidCID, s)
+	}
+
+	b, err := decodeMultibase(s)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	m, codec, version, err = FromCIDBytes(b)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if version == 0 {
+		// A CIDv0 has no multibase form.
+		return nil, 0, 0, fmt.Errorf("%w: multibase-encoded CIDv0", ErrInvalidCID)
+	}
+	return m, codec, version, nil
+}
+
+// FromCIDBytes is like FromCID for the binary form of a CID.
+func FromCIDBytes(b []byte) (m Multihash, codec uint64, version int, err error) {
+	if len(b) == 34 && b[0] == SHA2_256 && b[1] == 32 {
+		return Multihash(b), DagProtobuf, 0, nil
+	}
+
+	v, rest, err := uvarint(b)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: %s", ErrInvalidCID, err)
+	}
+	if v != 1 {
+		return nil, 0, 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidCID, v)
+	}
+	codec, rest, err = uvarint(rest)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: %s", ErrInvalidCID, err)
+	}
+	m, err = Cast(rest)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: %s", ErrInvalidCID, err)
+	}
+	return m, codec, 1, nil
+}
+
+// decodeMultibase decodes the multibase encodings commonly used for CIDs.
+func decodeMultibase(s string) ([]byte, error) {
+	var b []byte
+	var err error
+	prefix, data := s[0], s[1:]
+	switch prefix {
+	case 'b':
+		b, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(data))
+		if err == nil && strings.ToLower(data) != data {
+			err = errors.New("mixed case base32")
+		}
+	case 'B':
+		b, err = base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(data)
+	case 'z':
+		b, err = b58.Decode(data)
+	case 'f', 'F':
+		if (prefix == 'f') != (strings.ToLower(data) == data) {
+			err = errors.New("base16 case does not match prefix")
+			break
+		}
+		b, err = hex.DecodeString(data)
+	case 'k':
+		if strings.ToLower(data) != data {
+			err = errors.New("uppercase base36 with lowercase prefix")
+			break
+		}
+		b, err = decodeBase36(data)
+	case 'K':
+		if strings.ToUpper(data) != data {
+			err = errors.New("lowercase base36 with uppercase prefix")
+			break
+		}
+		b, err = decodeBase36(strings.ToLower(data))
+	case 'm':
+		b, err = base64.RawStdEncoding.DecodeString(data)
+	case 'u':
+		b, err = base64.RawURLEncoding.DecodeString(data)
+	default:
+		return nil, fmt.Errorf("%w: unsupported multibase prefix %q", ErrInvalidCID, prefix)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCID, err)
+	}
+	return b, nil
+}
+
+// decodeBase36 decodes lowercase base36, where each leading '0' stands for a
+// leading zero byte.
+func decodeBase36(s string) ([]byte, error) {
+	const alphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+	zeros := 0
+	for zeros < len(s) && s[zeros] == '0' {
+		zeros++
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(36)
+	for i := zeros; i < len(s); i++ {
+		d := strings.IndexByte(alphabet, s[i])
+		if d < 0 {
+			return nil, fmt.Errorf("invalid base36 character %q", s[i])
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,66 @@
+package multihash
+
+import (
+	"errors"
+	"testing"
+)
+
+// fooMultihash is the sha2-256 multihash of "foo".
+const fooMultihash = "12202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"
+
+func TestFromCID(t *testing.T) {
+	for _, tc := range []struct {
+		cid     string
+		codec   uint64
+		version int
+	}{
+		{"QmRJzsvyCQyizr73Gmms8ZRtvNxmgqumxc2KUp71dfEmoj", DagProtobuf, 0},
+		{"bafybeibme22gw2h7y2h7tg2fhqotaqjucnbc24deqo72b6mkl2egezxhvy", DagProtobuf, 1},
+		{"bafkreibme22gw2h7y2h7tg2fhqotaqjucnbc24deqo72b6mkl2egezxhvy", 0x55, 1},
+		{"BAFKREIBME22GW2H7Y2H7TG2FHQOTAQJUCNBC24DEQO72B6MKL2EGEZXHVY", 0x55, 1},
+		{"zb2rhZcdMzBSGEjx2xbFsY9pTpMgegGQLEWsNpPnUzbPwCx1T", 0x55, 1},
+		{"f015512202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", 0x55, 1},
+		{"F015512202C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE", 0x55, 1},
+		{"k2cwue9r0p518otr4itz8hs8b4wjk4nmepaw4lgybiheoa4csgoe6my6", 0x55, 1},
+		{"K2CWUE9R0P518OTR4ITZ8HS8B4WJK4NMEPAW4LGYBIHEOA4CSGOE6MY6", 0x55, 1},
+		{"mAVUSICwmtGto/8aP+ZtFPB0wQTQTQi1wZIO/oPmKXohiZueu", 0x55, 1},
+		{"uAVUSICwmtGto_8aP-ZtFPB0wQTQTQi1wZIO_oPmKXohiZueu", 0x55, 1},
+	} {
+		m, codec, version, err := FromCID(tc.cid)
+		if err != nil {
+			t.Errorf("%s: %s", tc.cid, err)
+			continue
+		}
+		if m.HexString() != fooMultihash {
+			t.Errorf("%s: unexpected multihash %s", tc.cid, m.HexString())
+		}
+		if codec != tc.codec || version != tc.version {
+			t.Errorf("%s: got codec 0x%x version %d, expected codec 0x%x version %d", tc.cid, codec, version, tc.codec, tc.version)
+		}
+	}
+}
+
+func TestFromCIDErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"b",
+		// Mixed case.
+		"bafkreibme22gw2h7y2h7tg2fhqotaqjucnbc24deqo72B6MKL2EGEZXHVY",
+		// Unsupported multibase.
+		"cafkreibme22gw2h7y2h7tg2fhqotaqjucnbc24deqo72b6mkl2egezxhvy",
+		// Version 2.
+		"f025512202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		// Truncated multihash.
+		"f015512202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7",
+		// Trailing data.
+		"f015512202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae00",
+		// A multibase-encoded CIDv0.
+		"f" + fooMultihash,
+		// Not base58.
+		"Qm0JzsvyCQyizr73Gmms8ZRtvNxmgqumxc2KUp71dfEmoj",
+	} {
+		if _, _, _, err := FromCID(s); !errors.Is(err, ErrInvalidCID) {
+			t.Errorf("%q: expected ErrInvalidCID, got %v", s, err)
+		}
+	}
+}
@@ -32,4 +32,24 @@ const (
 	MURMUR3X64_64 = 0x22
 	MD5           = 0xd5
 	DBL_SHA2_256  = 0x56
+
+	SHA2_256_TRUNC254_PADDED  = 0x1012
+	KANGAROOTWELVE            = 0x1d01
+	X11                       = 0x1100
+	POSEIDON_BLS12_381_A2_FC1 = 0xb401
+
+	RIPEMD_128 = 0x1052
+	RIPEMD_160 = 0x1053
+	RIPEMD_256 = 0x1054
+	RIPEMD_320 = 0x1055
+	SM3_256    = 0x534d
+	MD4        = 0xd4
+
+	XXH_32   = 0xb3e1
+	XXH_64   = 0xb3e2
+	XXH3_64  = 0xb3e3
+	XXH3_128 = 0xb3e4
+
+	MURMUR3_32     = 0x23
+	MURMUR3X64_128 = 0x1022
 )
@@ -0,0 +1,81 @@
+package multihash
+
+// Strength describes how much a hash function can be trusted to identify
+// data, so that the strongest of several digests can be chosen.
+type Strength struct {
+	// Bits is the collision resistance, in bits, of the untruncated digest.
+	// For broken functions it is the cost of the best known attack.
+	Bits int
+	// Cryptographic is false for checksums and fast non-cryptographic
+	// hashes, which offer no security at all. It is also false for digests
+	// which pad their input without encoding its length, such as Merkle tree
+	// roots, since they collide on inputs differing only in padding.
+	Cryptographic bool
+	// Broken is set when practical collision attacks are known.
+	Broken bool
+}
+
+// EffectiveBits returns the collision resistance of a digest truncated to
+// length bytes: at most half its bits, and none for non-cryptographic hashes.
+func (s Strength) EffectiveBits(length int) int {
+	if !s.Cryptographic {
+		return 0
+	}
+	if bits := length * 8 / 2; bits < s.Bits {
+		return bits
+	}
+	return s.Bits
+}
+
+// Less reports whether s is weaker than t for digests of the given lengths.
+// Non-cryptographic functions rank below broken ones, which rank below sound
+// ones; within each class, fewer effective bits rank lower.
+func (s Strength) Less(length int, t Strength, tLength int) bool {
+	if s.class() != t.class() {
+		return s.class() < t.class()
+	}
+	return s.EffectiveBits(length) < t.EffectiveBits(tLength)
+}
+
+func (s Strength) class() int {
+	switch {
+	case !s.Cryptographic:
+		return 0
+	case s.Broken:
+		return 1
+	default:
+		return 2
+	}
+}
+
+var strengths = map[uint64]Strength{}
+
+// RegisterStrength records the strength of the hash function with the given
+// code. Packages registering hash functions should call it alongside
+// Register.
+//
+// Like Register, it has a global effect and should only be used at package
+// init time.
+func RegisterStrength(indicator uint64, s Strength) {
+	strengths[indicator] = s
+}
+
+// GetStrength returns the strength recorded for a code, and false if there
+// is none.
+func GetStrength(indicator uint64) (Strength, bool) {
+	s, ok := strengths[indicator]
+	return s, ok
+}
+
+func init() {
+	RegisterStrength(IDENTITY, Strength{})
+	RegisterStrength(MD5, Strength{Bits: 18, Cryptographic: true, Broken: true})
+	RegisterStrength(SHA1, Strength{Bits: 63, Cryptographic: true, Broken: true})
+	RegisterStrength(SHA2_224, Strength{Bits: 112, Cryptographic: true})
+	RegisterStrength(SHA2_256, Strength{Bits: 128, Cryptographic: true})
+	RegisterStrength(SHA2_384, Strength{Bits: 192, Cryptographic: true})
+	RegisterStrength(SHA2_512, Strength{Bits: 256, Cryptographic: true})
+	RegisterStrength(SHA2_512_224, Strength{Bits: 112, Cryptographic: true})
+	RegisterStrength(SHA2_512_256, Strength{Bits: 128, Cryptographic: true})
+	RegisterStrength(DBL_SHA2_256, Strength{Bits: 128, Cryptographic: true})
+}
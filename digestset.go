@@ -0,0 +1,191 @@
+package multihash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sort"
+
+	mhreg "github.com/multiformats/go-multihash/core"
+)
+
+// ErrInvalidDigestSet is returned when a digest set holds a malformed digest,
+// or a multihash cannot be written into one.
+var ErrInvalidDigestSet = errors.New("invalid digest set")
+
+// DigestSet maps algorithm names to hex digests, as in the "digest" field of
+// in-toto and SLSA attestations, or the checksums of SPDX and CycloneDX
+// SBOMs.
+type DigestSet map[string]string
+
+// DigestVocabulary selects the algorithm names used in a DigestSet.
+type DigestVocabulary int
+
+const (
+	// InToto names algorithms as in-toto DigestSets do, e.g. "sha256".
+	InToto DigestVocabulary = iota
+	// SPDX names algorithms as SPDX checksums do, e.g. "SHA256".
+	SPDX
+	// CycloneDX names algorithms as CycloneDX hashes do, e.g. "SHA-256".
+	CycloneDX
+)
+
+// digestNames maps multihash codes to the names of each vocabulary. An
+// empty name means the vocabulary has no name for the algorithm.
+var digestNames = []struct {
+	code  uint64
+	names [3]string
+}{
+	{MD5, [3]string{"md5", "MD5", "MD5"}},
+	{SHA1, [3]string{"sha1", "SHA1", "SHA-1"}},
+	{mhreg.SHA2_224, [3]string{"sha224", "SHA224", ""}},
+	{SHA2_256, [3]string{"sha256", "SHA256", "SHA-256"}},
+	{SHA2_384, [3]string{"sha384", "SHA384", "SHA-384"}},
+	{SHA2_512, [3]string{"sha512", "SHA512", "SHA-512"}},
+	{mhreg.SHA2_512_224, [3]string{"sha512_224", "", ""}},
+	{mhreg.SHA2_512_256, [3]string{"sha512_256", "", ""}},
+	{SHA3_224, [3]string{"sha3_224", "", ""}},
+	{SHA3_256, [3]string{"sha3_256", "SHA3-256", "SHA3-256"}},
+	{SHA3_384, [3]string{"sha3_384", "SHA3-384", "SHA3-384"}},
+	{SHA3_512, [3]string{"sha3_512", "SHA3-512", "SHA3-512"}},
+	{BLAKE2B_MIN + 31, [3]string{"", "BLAKE2b-256", "BLAKE2b-256"}},
+	{BLAKE2B_MIN + 47, [3]string{"", "BLAKE2b-384", "BLAKE2b-384"}},
+	{BLAKE2B_MIN + 63, [3]string{"blake2b", "BLAKE2b-512", "BLAKE2b-512"}},
+	{BLAKE2S_MIN + 31, [3]string{"blake2s", "", ""}},
+	{BLAKE3, [3]string{"", "BLAKE3", "BLAKE3"}},
+}
+
+// digestCode looks a name up in every vocabulary; their names do not clash.
+func digestCode(name string) (uint64, bool) {
+	for _, d := range digestNames {
+		for _, n := range d.names {
+			if n != "" && n == name {
+				return d.code, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// NewDigestSet builds a DigestSet from multihashes, naming the algorithms
+// after the given vocabulary. Truncated multihashes, and algorithms the
+// vocabulary has no name for, cannot be represented.
+func NewDigestSet(mhs []Multihash, vocab DigestVocabulary) (DigestSet, error) {
+	if vocab < InToto || vocab > CycloneDX {
+		return nil, fmt.Errorf("unknown digest vocabulary %d", vocab)
+	}
+
+	ds := make(DigestSet, len(mhs))
+	for _, m := range mhs {
+		dm, err := decode(m)
+		if err != nil {
+			return nil, err
+		}
+		name := ""
+		for _, d := range digestNames {
+			if d.code == dm.Code {
+				name = d.names[vocab]
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%w: no name for code 0x%x", ErrInvalidDigestSet, dm.Code)
+		}
+		if size, ok := DefaultLengths[dm.Code]; ok && dm.Length != size {
+			return nil, fmt.Errorf("%w: %s multihash is truncated to %d bytes", ErrInvalidDigestSet, name, dm.Length)
+		}
+		ds[name] = hex.EncodeToString(dm.Digest)
+	}
+	return ds, nil
+}
+
+// Multihashes returns the digests of the set as multihashes, ordered by
+// code. Names from any of the vocabularies are accepted; entries with other
+// names, such as in-toto's "gitCommit", are skipped.
+func (ds DigestSet) Multihashes() ([]Multihash, error) {
+	mhs := make([]Multihash, 0, len(ds))
+	codes := make([]uint64, 0, len(ds))
+	for name, value := range ds {
+		code, ok := digestCode(name)
+		if !ok {
+			continue
+		}
+		digest, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %s", ErrInvalidDigestSet, name, err)
+		}
+		if size, ok := DefaultLengths[code]; ok && len(digest) != size {
+			return nil, fmt.Errorf("%w: %s digest is %d bytes, expected %d", ErrInvalidDigestSet, name, len(digest), size)
+		}
+		m, err := Encode(digest, code)
+		if err != nil {
+			return nil, err
+		}
+		mhs = append(mhs, m)
+		codes = append(codes, code)
+	}
+	sort.Sort(byCode{mhs, codes})
+	return mhs, nil
+}
+
+type byCode struct {
+	mhs   []Multihash
+	codes []uint64
+}
+
+func (s byCode) Len() int           { return len(s.mhs) }
+func (s byCode) Less(i, j int) bool { return s.codes[i] < s.codes[j] }
+func (s byCode) Swap(i, j int) {
+	s.mhs[i], s.mhs[j] = s.mhs[j], s.mhs[i]
+	s.codes[i], s.codes[j] = s.codes[j], s.codes[i]
+}
+
+// Verify reads r to the end and checks it against every digest of the set
+// whose algorithm is known and has a registered hasher, hashing the data in a
+// single pass. Other entries are ignored, but an error is returned if none
+// can be checked.
+func (ds DigestSet) Verify(r io.Reader) error {
+	// Validate every known entry, not only those with a hasher.
+	if _, err := ds.Multihashes(); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(ds))
+	for name := range ds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var writers []io.Writer
+	var hashers []hash.Hash
+	var checked []string
+	for _, name := range names {
+		code, ok := digestCode(name)
+		if !ok {
+			continue
+		}
+		h, err := GetHasher(code)
+		if err != nil {
+			continue
+		}
+		writers = append(writers, h)
+		hashers = append(hashers, h)
+		checked = append(checked, name)
+	}
+	if len(hashers) == 0 {
+		return fmt.Errorf("%w: no supported algorithm", ErrInvalidDigestSet)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(writers...), r); err != nil {
+		return err
+	}
+	for i, h := range hashers {
+		expected, _ := hex.DecodeString(ds[checked[i]])
+		if actual := h.Sum(nil); !bytes.Equal(actual, expected) {
+			return fmt.Errorf("%w: %s is %x, expected %x", ErrDigestMismatch, checked[i], actual, expected)
+		}
+	}
+	return nil
+}
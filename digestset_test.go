@@ -0,0 +1,123 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const (
+	helloSHA256 = "a948904f2f0f479b8f8197694b30184b0d2ed1c1cd2a1ec0fb85d299a192a447"
+	helloSHA512 = "db3974a97f2407b7cae1ae637c0030687a11913274d578492558e39c16c017de84eacdc8c62fe34ee4e12b4b1428817f09b6a2760c3f8a664ceae94d2434a593"
+	helloBLAKE2 = "c71b05fd1d1c7bf7e928ff18e58db5193e9316416cc26ba9cc9094da80d7011e"
+)
+
+func TestDigestSetVocabularies(t *testing.T) {
+	data := []byte("hello world\n")
+	var mhs []Multihash
+	for _, code := range []uint64{SHA2_256, SHA2_512, BLAKE2B_MIN + 31} {
+		m, err := Sum(data, code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mhs = append(mhs, m)
+	}
+
+	if _, err := NewDigestSet(mhs, InToto); !errors.Is(err, ErrInvalidDigestSet) {
+		t.Errorf("expected ErrInvalidDigestSet for blake2b-256 in in-toto, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		vocab    DigestVocabulary
+		expected DigestSet
+	}{
+		{SPDX, DigestSet{"SHA256": helloSHA256, "SHA512": helloSHA512, "BLAKE2b-256": helloBLAKE2}},
+		{CycloneDX, DigestSet{"SHA-256": helloSHA256, "SHA-512": helloSHA512, "BLAKE2b-256": helloBLAKE2}},
+	} {
+		ds, err := NewDigestSet(mhs, tc.vocab)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ds) != len(tc.expected) {
+			t.Errorf("vocabulary %d: expected %v, got %v", tc.vocab, tc.expected, ds)
+		}
+		for name, value := range tc.expected {
+			if ds[name] != value {
+				t.Errorf("vocabulary %d: %s is %q, expected %q", tc.vocab, name, ds[name], value)
+			}
+		}
+
+		back, err := ds.Multihashes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(back) != len(mhs) {
+			t.Fatalf("vocabulary %d: round trip returned %d multihashes", tc.vocab, len(back))
+		}
+		for i := range mhs {
+			if !bytes.Equal(back[i], mhs[i]) {
+				t.Errorf("vocabulary %d: round trip mismatch at %d", tc.vocab, i)
+			}
+		}
+	}
+}
+
+func TestDigestSetInToto(t *testing.T) {
+	ds := DigestSet{
+		"sha256":    helloSHA256,
+		"sha512":    helloSHA512,
+		"sha224":    "95041dd60ab08c0bf5636d50be85fe9790300f39eb84602858a9b430",
+		"gitCommit": "3c8bd6b4d2a5b3e5f8f5b13f2a6dd1e4c0c0ae7d",
+	}
+	mhs, err := ds.Multihashes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 3 {
+		t.Fatalf("expected 3 multihashes, got %d", len(mhs))
+	}
+	if mhs[0].HexString() != "1220"+helloSHA256 {
+		t.Errorf("expected sha2-256 first, got %s", mhs[0].HexString())
+	}
+
+	if err := ds.Verify(strings.NewReader("hello world\n")); err != nil {
+		t.Errorf("verify: %s", err)
+	}
+	if err := ds.Verify(strings.NewReader("hello world")); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected ErrDigestMismatch, got %v", err)
+	}
+
+	// A single wrong digest fails verification even when others match.
+	ds["sha512_256"] = strings.Repeat("00", 32)
+	if err := ds.Verify(strings.NewReader("hello world\n")); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected ErrDigestMismatch, got %v", err)
+	}
+}
+
+func TestDigestSetErrors(t *testing.T) {
+	for _, ds := range []DigestSet{
+		{"sha256": "zz"},
+		{"sha256": helloSHA256[:62]},
+		{"SHA-1": helloSHA256},
+	} {
+		if _, err := ds.Multihashes(); !errors.Is(err, ErrInvalidDigestSet) {
+			t.Errorf("%v: expected ErrInvalidDigestSet, got %v", ds, err)
+		}
+		if err := ds.Verify(strings.NewReader("")); !errors.Is(err, ErrInvalidDigestSet) {
+			t.Errorf("%v: expected ErrInvalidDigestSet from Verify, got %v", ds, err)
+		}
+	}
+
+	if err := (DigestSet{"gitCommit": "00"}).Verify(strings.NewReader("")); !errors.Is(err, ErrInvalidDigestSet) {
+		t.Errorf("expected ErrInvalidDigestSet with no supported algorithm, got %v", err)
+	}
+
+	trunc, err := Sum([]byte("foo"), SHA2_256, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDigestSet([]Multihash{trunc}, InToto); !errors.Is(err, ErrInvalidDigestSet) {
+		t.Errorf("expected ErrInvalidDigestSet for a truncated multihash, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package multihash
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidGitObject is returned for malformed Git object IDs and objects,
+// and for multihashes which cannot be used as Git object IDs.
+var ErrInvalidGitObject = errors.New("invalid git object")
+
+// gitCodes are the hash functions of the Git object formats.
+var gitCodes = map[uint64]string{
+	SHA1:     "sha1",
+	SHA2_256: "sha256",
+}
+
+// SumGitBlob computes the object ID of a Git blob holding the size bytes read
+// from r, that is the hash of "blob <size>\x00" followed by the content.
+//
+// The code selects the repository object format: SHA1 for the classic sha1
+// format and SHA2_256 for the sha256 format. Exactly size bytes are read
+// from r; an error is returned if it ends before that.
+func SumGitBlob(r io.Reader, size int64, code uint64) (Multihash, error) {
+	return SumGitObject("blob", r, size, code)
+}
+
+// SumGitObject is like SumGitBlob for any object type, such as "tree" or
+// "commit".
+func SumGitObject(kind string, r io.Reader, size int64, code uint64) (Multihash, error) {
+	if _, ok := gitCodes[code]; !ok {
+		return nil, fmt.Errorf("%w: no git object format for code 0x%x", ErrInvalidGitObject, code)
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("%w: negative size", ErrInvalidGitObject)
+	}
+
+	header := strings.NewReader(kind + " " + strconv.FormatInt(size, 10) + "\x00")
+	content := &io.LimitedReader{R: r, N: size}
+	m, err := SumStream(io.MultiReader(header, content), code, -1)
+	if err != nil {
+		return nil, err
+	}
+	if content.N != 0 {
+		return nil, fmt.Errorf("%w: content is %d bytes short of %d", io.ErrUnexpectedEOF, content.N, size)
+	}
+	return m, nil
+}
+
+// FromGitOID parses a hex Git object ID. Forty characters are a sha1 object
+// ID and sixty-four a sha256 one.
+func FromGitOID(s string) (Multihash, error) {
+	var code uint64
+	switch len(s) {
+	case 40:
+		code = SHA1
+	case 64:
+		code = SHA2_256
+	default:
+		return nil, fmt.Errorf("%w: object ID %q must be 40 or 64 hex characters", ErrInvalidGitObject, s)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGitObject, err)
+	}
+	return Encode(b, code)
+}
+
+// GitOID returns the hex Git object ID of an untruncated sha1 or sha2-256
+// multihash.
+func (m Multihash) GitOID() (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := gitCodes[dm.Code]; !ok {
+		return "", fmt.Errorf("%w: %s is not a git object format", ErrInvalidGitObject, dm.Name)
+	}
+	if dm.Length != DefaultLengths[dm.Code] {
+		return "", fmt.Errorf("%w: %s multihash is truncated to %d bytes", ErrInvalidGitObject, dm.Name, dm.Length)
+	}
+	return hex.EncodeToString(dm.Digest), nil
+}
+
+// ReadGitObject reads a loose object from a Git directory (such as
+// "repo/.git"), returning its type and content. The object format follows
+// from the multihash code, and the content is checked against the object ID.
+//
+// Objects stored in pack files are not supported.
+func ReadGitObject(gitDir string, oid Multihash) (kind string, content []byte, err error) {
+	s, err := oid.GitOID()
+	if err != nil {
+		return "", nil, err
+	}
+	dm, err := decode(oid)
+	if err != nil {
+		return "", nil, err
+	}
+
+	f, err := os.Open(filepath.Join(gitDir, "objects", s[:2], s[2:]))
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: %s", ErrInvalidGitObject, s, err)
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+	header, err := br.ReadString(0)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: missing header", ErrInvalidGitObject, s)
+	}
+	kind, sizeStr, ok := strings.Cut(header[:len(header)-1], " ")
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s: malformed header %q", ErrInvalidGitObject, s, header)
+	}
+	size, err := strconv.ParseInt(sizeStr, 10, 64)
+	if err != nil || size < 0 {
+		return "", nil, fmt.Errorf("%w: %s: malformed size %q", ErrInvalidGitObject, s, sizeStr)
+	}
+
+	content, err = io.ReadAll(br)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s: %s", ErrInvalidGitObject, s, err)
+	}
+	if int64(len(content)) != size {
+		return "", nil, fmt.Errorf("%w: %s: content is %d bytes, header says %d", ErrInvalidGitObject, s, len(content), size)
+	}
+
+	actual, err := SumGitObject(kind, bytes.NewReader(content), size, dm.Code)
+	if err != nil {
+		return "", nil, err
+	}
+	if !bytes.Equal(actual, oid) {
+		got, _ := actual.GitOID()
+		return "", nil, fmt.Errorf("%w: object %s hashes to %s", ErrDigestMismatch, s, got)
+	}
+	return kind, content, nil
+}
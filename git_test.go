@@ -0,0 +1,131 @@
+package multihash
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var gitTestCases = []struct {
+	content string
+	sha1    string
+	sha256  string
+}{
+	{"", "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", "473a0f4c3be8a93681a267e3b1e9a7dcda1185436fe141f7749120a303721813"},
+	{"hello world\n", "3b18e512dba79e4c8300dd08aeb37f8e728b8dad", "0bd69098bd9b9cc5934a610ab65da429b525361147faa7b5b922919e9a23143d"},
+}
+
+func TestSumGitBlob(t *testing.T) {
+	for _, tc := range gitTestCases {
+		for code, oid := range map[uint64]string{SHA1: tc.sha1, SHA2_256: tc.sha256} {
+			m, err := SumGitBlob(strings.NewReader(tc.content), int64(len(tc.content)), code)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := m.GitOID()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != oid {
+				t.Errorf("%q: expected %s, got %s", tc.content, oid, s)
+			}
+
+			m2, err := FromGitOID(oid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(m, m2) {
+				t.Errorf("%s: FromGitOID mismatch", oid)
+			}
+		}
+	}
+}
+
+func TestSumGitBlobErrors(t *testing.T) {
+	if _, err := SumGitBlob(strings.NewReader("foo"), 4, SHA1); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	r := strings.NewReader("hello world\nmore")
+	if m, err := SumGitBlob(r, 12, SHA1); err != nil {
+		t.Error(err)
+	} else if s, _ := m.GitOID(); s != gitTestCases[1].sha1 {
+		t.Errorf("unexpected object ID %s", s)
+	} else if r.Len() != 4 {
+		t.Errorf("expected the rest of the reader to be left, %d bytes remain", r.Len())
+	}
+	if _, err := SumGitBlob(strings.NewReader("foo"), 3, MD5); !errors.Is(err, ErrInvalidGitObject) {
+		t.Errorf("expected ErrInvalidGitObject, got %v", err)
+	}
+}
+
+func TestGitOIDErrors(t *testing.T) {
+	for _, s := range []string{"", "e69de29b", "e69de29bb2d1d6434b8b29ae775ad8c2e48c539z"} {
+		if _, err := FromGitOID(s); !errors.Is(err, ErrInvalidGitObject) {
+			t.Errorf("%q: expected ErrInvalidGitObject, got %v", s, err)
+		}
+	}
+
+	trunc, err := Sum(nil, SHA1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trunc.GitOID(); !errors.Is(err, ErrInvalidGitObject) {
+		t.Errorf("expected ErrInvalidGitObject for a truncated multihash, got %v", err)
+	}
+	md5, err := Sum(nil, MD5, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := md5.GitOID(); !errors.Is(err, ErrInvalidGitObject) {
+		t.Errorf("expected ErrInvalidGitObject for md5, got %v", err)
+	}
+}
+
+// The loose objects in testdata were written by git hash-object -w, in a
+// sha1 and a sha256 repository.
+func TestReadGitObject(t *testing.T) {
+	for _, tc := range gitTestCases {
+		for dir, oid := range map[string]string{"testdata/git-sha1": tc.sha1, "testdata/git-sha256": tc.sha256} {
+			m, err := FromGitOID(oid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			kind, content, err := ReadGitObject(dir, m)
+			if err != nil {
+				t.Fatalf("%s: %s", oid, err)
+			}
+			if kind != "blob" || string(content) != tc.content {
+				t.Errorf("%s: unexpected %s %q", oid, kind, content)
+			}
+		}
+	}
+}
+
+func TestReadGitObjectCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	oid := gitTestCases[1].sha1
+	if err := os.MkdirAll(filepath.Join(dir, "objects", oid[:2]), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte("blob 12\x00hello there\n"))
+	zw.Close()
+	if err := os.WriteFile(filepath.Join(dir, "objects", oid[:2], oid[2:]), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := FromGitOID(oid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadGitObject(dir, m); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected ErrDigestMismatch, got %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 module github.com/multiformats/go-multihash
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/minio/sha256-simd v1.0.0
 	github.com/mr-tron/base58 v1.2.0
 	github.com/multiformats/go-varint v0.0.6
 	github.com/spaolacci/murmur3 v1.1.0
+	github.com/zeebo/xxh3 v1.0.2
 	golang.org/x/crypto v0.35.0
 	lukechampine.com/blake3 v1.1.6
 )
@@ -0,0 +1,124 @@
+package multihash
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidGoModuleHash is returned for malformed go.sum hashes, multihashes
+// which cannot be written as one, and module trees which cannot be hashed.
+var ErrInvalidGoModuleHash = errors.New("invalid go module hash")
+
+// goModuleHashPrefix is the prefix of the only go.sum hash format, "h1".
+const goModuleHashPrefix = "h1:"
+
+// SumGoModuleDir computes the go.sum "h1:" hash of the module tree in fsys,
+// naming each file prefix + "/" + its path, where prefix is usually
+// "<module path>@<version>". The result is a sha2-256 multihash.
+func SumGoModuleDir(fsys fs.FS, prefix string) (Multihash, error) {
+	var names []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			names = append(names, prefix+"/"+name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sumGoModule(names, func(name string) (io.ReadCloser, error) {
+		return fsys.Open(strings.TrimPrefix(name, prefix+"/"))
+	})
+}
+
+// SumGoModuleZip computes the go.sum "h1:" hash of a module zip, such as
+// those in the module cache or served by a module proxy, whose size bytes
+// are read from r. File names are used as stored in the zip, which already
+// carry the "<module path>@<version>/" prefix.
+func SumGoModuleZip(r io.ReaderAt, size int64) (Multihash, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	names := make([]string, 0, len(zr.File))
+	for _, f := range zr.File {
+		if _, ok := files[f.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate file %q in zip", ErrInvalidGoModuleHash, f.Name)
+		}
+		files[f.Name] = f
+		names = append(names, f.Name)
+	}
+	return sumGoModule(names, func(name string) (io.ReadCloser, error) {
+		return files[name].Open()
+	})
+}
+
+// sumGoModule implements the "h1" hash of golang.org/x/mod/sumdb/dirhash:
+// the sha256 of a summary holding one "<sha256 hex>  <name>\n" line per
+// file, sorted by name.
+func sumGoModule(names []string, open func(string) (io.ReadCloser, error)) (Multihash, error) {
+	for _, name := range names {
+		if strings.Contains(name, "\n") {
+			return nil, fmt.Errorf("%w: file name %q contains a newline", ErrInvalidGoModuleHash, name)
+		}
+	}
+	sort.Strings(names)
+
+	summary := sha256.New()
+	for _, name := range names {
+		f, err := open(name)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(summary, "%x  %s\n", h.Sum(nil), name)
+	}
+	return Encode(summary.Sum(nil), SHA2_256)
+}
+
+// FromGoModuleHash parses a go.sum hash, "h1:<base64>", into a sha2-256
+// multihash.
+func FromGoModuleHash(s string) (Multihash, error) {
+	value, ok := strings.CutPrefix(s, goModuleHashPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q does not start with %q", ErrInvalidGoModuleHash, s, goModuleHashPrefix)
+	}
+	digest, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidGoModuleHash, err)
+	}
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("%w: digest is %d bytes, expected %d", ErrInvalidGoModuleHash, len(digest), sha256.Size)
+	}
+	return Encode(digest, SHA2_256)
+}
+
+// GoModuleHash returns the go.sum "h1:<base64>" form of an untruncated
+// sha2-256 multihash.
+func (m Multihash) GoModuleHash() (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+	if dm.Code != SHA2_256 || dm.Length != sha256.Size {
+		return "", fmt.Errorf("%w: %s multihash of %d bytes is not an h1 hash", ErrInvalidGoModuleHash, dm.Name, dm.Length)
+	}
+	return goModuleHashPrefix + base64.StdEncoding.EncodeToString(dm.Digest), nil
+}
@@ -0,0 +1,112 @@
+package multihash
+
+import (
+	"archive/zip"
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+// The go.sum line for the module zip in testdata/gomod.
+const (
+	goVarintPrefix = "github.com/multiformats/go-varint@v0.0.6"
+	goVarintHash   = "h1:gk85QWKxh3TazbLxED/NlDVv8+q+ReFJk7Y2W/KhfNY="
+)
+
+func TestSumGoModuleZip(t *testing.T) {
+	f, err := os.Open("testdata/gomod/go-varint-v0.0.6.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := SumGoModuleZip(f, fi.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := m.GoModuleHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != goVarintHash {
+		t.Errorf("expected %s, got %s", goVarintHash, s)
+	}
+
+	// The same tree, hashed as a directory, must give the same hash.
+	zr, err := zip.NewReader(f, fi.Size())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := fs.Sub(zr, goVarintPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2, err := SumGoModuleDir(dir, goVarintPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m2.String() != m.String() {
+		t.Errorf("directory hash %s differs from zip hash %s", m2, m)
+	}
+}
+
+func TestSumGoModuleDir(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":      {Data: []byte("module example.com/m\n")},
+		"m.go":        {Data: []byte("package m\n")},
+		"sub/sub.go":  {Data: []byte("package sub\n")},
+		"sub/empty.c": {Data: nil},
+	}
+	m, err := SumGoModuleDir(fsys, "example.com/m@v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := SumGoModuleDir(fsys, "example.com/m@v1.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.String() == other.String() {
+		t.Error("the prefix must be part of the hash")
+	}
+
+	if _, err := SumGoModuleDir(fstest.MapFS{"a\nb": {}}, "example.com/m@v1.0.0"); !errors.Is(err, ErrInvalidGoModuleHash) {
+		t.Errorf("expected ErrInvalidGoModuleHash for a newline in a name, got %v", err)
+	}
+}
+
+func TestGoModuleHash(t *testing.T) {
+	m, err := FromGoModuleHash(goVarintHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.HexString() != "1220824f394162b18774dacdb2f1103fcd94356ff3eabe45e14993b6365bf2a17cd6" {
+		t.Errorf("unexpected multihash %s", m.HexString())
+	}
+	s, err := m.GoModuleHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != goVarintHash {
+		t.Errorf("round trip: expected %s, got %s", goVarintHash, s)
+	}
+
+	for _, s := range []string{"", "h2:gk85QWKxh3TazbLxED/NlDVv8+q+ReFJk7Y2W/KhfNY=", "h1:gk85QWKxh3TazbLxED", "h1:!!!!"} {
+		if _, err := FromGoModuleHash(s); !errors.Is(err, ErrInvalidGoModuleHash) {
+			t.Errorf("%q: expected ErrInvalidGoModuleHash, got %v", s, err)
+		}
+	}
+
+	sha1, err := Sum([]byte("foo"), SHA1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sha1.GoModuleHash(); !errors.Is(err, ErrInvalidGoModuleHash) {
+		t.Errorf("expected ErrInvalidGoModuleHash for sha1, got %v", err)
+	}
+}
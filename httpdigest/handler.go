@@ -0,0 +1,98 @@
+package httpdigest
+
+import (
+	"bytes"
+	"net/http"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Handler returns a handler which adds a Content-Digest field to the responses
+// of next.
+//
+// The algorithm is picked from the request's Want-Content-Digest field when it
+// names a registered algorithm; otherwise the given codes are used, or
+// sha-256 if none are given. Responses which already carry a Content-Digest
+// field, responses to HEAD requests and responses without content are left
+// untouched.
+//
+// The whole response body is buffered in order to send the field before the
+// content, so Handler is not suited to streaming responses.
+func Handler(next http.Handler, codes ...uint64) http.Handler {
+	if len(codes) == 0 {
+		codes = []uint64{mh.SHA2_256}
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(bw, r)
+
+		if hasContent(bw.status) && w.Header().Get(ContentDigest) == "" {
+			if value, err := digestValue(bw.body.Bytes(), selectCodes(r, codes)); err == nil {
+				w.Header().Set(ContentDigest, value)
+			}
+		}
+
+		w.WriteHeader(bw.status)
+		w.Write(bw.body.Bytes())
+	})
+}
+
+// selectCodes picks the most preferred registered algorithm of the request,
+// falling back to the defaults.
+func selectCodes(r *http.Request, defaults []uint64) []uint64 {
+	want := r.Header.Get(WantContentDigest)
+	if want == "" {
+		return defaults
+	}
+	prefs, err := ParseWant(want)
+	if err != nil {
+		return defaults
+	}
+	for _, p := range prefs {
+		if _, err := mh.GetHasher(p.Code); err == nil {
+			return []uint64{p.Code}
+		}
+	}
+	return defaults
+}
+
+func digestValue(body []byte, codes []uint64) (string, error) {
+	mhs := make([]mh.Multihash, 0, len(codes))
+	for _, code := range codes {
+		m, err := mh.Sum(body, code, -1)
+		if err != nil {
+			return "", err
+		}
+		mhs = append(mhs, m)
+	}
+	return FormatDigest(mhs...)
+}
+
+func hasContent(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// bufferedWriter holds back the status and body until the digest is known.
+type bufferedWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status, w.wroteHeader = status, true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
@@ -0,0 +1,252 @@
+// Package httpdigest implements the HTTP integrity fields of RFC 9530
+// (Content-Digest, Repr-Digest and their Want- counterparts) on top of
+// multihashes.
+//
+// Field values are converted to and from multihashes, and digests are
+// computed through the multihash registry, so any registered implementation
+// of the hash functions is used.
+package httpdigest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Field names defined by RFC 9530.
+const (
+	ContentDigest     = "Content-Digest"
+	ReprDigest        = "Repr-Digest"
+	WantContentDigest = "Want-Content-Digest"
+	WantReprDigest    = "Want-Repr-Digest"
+)
+
+// ErrNoDigest is returned when a field holds no digest for a supported
+// algorithm.
+var ErrNoDigest = errors.New("no supported digest")
+
+// ErrMismatch is returned when content does not match its digest field.
+// It matches multihash.ErrDigestMismatch with errors.Is.
+type ErrMismatch struct {
+	Algorithm string
+	Expected  []byte
+	Actual    []byte
+}
+
+func (e ErrMismatch) Error() string {
+	return fmt.Sprintf("%s: %s digest mismatch: expected :%x:, got :%x:", mh.ErrDigestMismatch, e.Algorithm, e.Expected, e.Actual)
+}
+
+func (e ErrMismatch) Is(target error) bool {
+	return target == mh.ErrDigestMismatch
+}
+
+// algorithms lists the entries of the HTTP Digest Algorithm Values registry
+// which have a multihash equivalent, from strongest to weakest. The
+// deprecated md5 and sha entries are understood when parsing but never
+// produced by default.
+var algorithms = []struct {
+	name       string
+	code       uint64
+	deprecated bool
+}{
+	{"sha-512", mh.SHA2_512, false},
+	{"sha-256", mh.SHA2_256, false},
+	{"sha", mh.SHA1, true},
+	{"md5", mh.MD5, true},
+}
+
+// Code returns the multihash code of an RFC 9530 algorithm name.
+func Code(algorithm string) (uint64, bool) {
+	for _, a := range algorithms {
+		if a.name == algorithm {
+			return a.code, true
+		}
+	}
+	return 0, false
+}
+
+// Algorithm returns the RFC 9530 algorithm name of a multihash code.
+func Algorithm(code uint64) (string, bool) {
+	for _, a := range algorithms {
+		if a.code == code {
+			return a.name, true
+		}
+	}
+	return "", false
+}
+
+// strength orders codes, lower is stronger.
+func strength(code uint64) int {
+	for i, a := range algorithms {
+		if a.code == code {
+			return i
+		}
+	}
+	return len(algorithms)
+}
+
+// ParseDigest parses a Content-Digest or Repr-Digest field value into
+// multihashes. Entries for algorithms without a multihash equivalent are
+// skipped.
+func ParseDigest(value string) ([]mh.Multihash, error) {
+	members, err := parseDictionary(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []mh.Multihash
+	for _, m := range members {
+		code, ok := Code(m.key)
+		if !ok {
+			continue
+		}
+		if m.kind != kindBytes {
+			return nil, fmt.Errorf("%w: %s value is not a byte sequence", ErrSyntax, m.key)
+		}
+		if l, ok := mh.DefaultLengths[code]; ok && l != len(m.bytes) {
+			return nil, fmt.Errorf("%w: %s digest is %d bytes, expected %d", ErrSyntax, m.key, len(m.bytes), l)
+		}
+		digest, err := mh.Encode(m.bytes, code)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, digest)
+	}
+	return out, nil
+}
+
+// FormatDigest formats multihashes as a Content-Digest or Repr-Digest field
+// value, such as "sha-256=:LCa0a2j/xo/5m0U8HTBBNBNCLXBkg7+g+YpeiGJm564=:".
+// Truncated multihashes and hash functions without an RFC 9530 name are
+// rejected.
+func FormatDigest(mhs ...mh.Multihash) (string, error) {
+	var b strings.Builder
+	for i, m := range mhs {
+		dm, err := mh.Decode(m)
+		if err != nil {
+			return "", err
+		}
+		name, ok := Algorithm(dm.Code)
+		if !ok {
+			return "", fmt.Errorf("%s has no HTTP digest algorithm name", dm.Name)
+		}
+		if dm.Length != mh.DefaultLengths[dm.Code] {
+			return "", fmt.Errorf("%s multihash is truncated to %d bytes", dm.Name, dm.Length)
+		}
+
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteString("=:")
+		b.WriteString(encodeBytes(dm.Digest))
+		b.WriteString(":")
+	}
+	return b.String(), nil
+}
+
+// Preference is a single entry of a Want-Content-Digest or Want-Repr-Digest
+// field.
+type Preference struct {
+	Algorithm string
+	Code      uint64
+	// Weight ranges from 1 (least preferred) to 10 (most preferred).
+	Weight int
+}
+
+// ParseWant parses a Want-Content-Digest or Want-Repr-Digest field value.
+//
+// The result is sorted from most to least preferred. Entries with weight 0
+// (not acceptable) and algorithms without a multihash equivalent are left
+// out.
+func ParseWant(value string) ([]Preference, error) {
+	members, err := parseDictionary(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Preference
+	for _, m := range members {
+		if m.kind != kindInteger || m.integer < 0 || m.integer > 10 {
+			return nil, fmt.Errorf("%w: %s preference must be an integer from 0 to 10", ErrSyntax, m.key)
+		}
+		code, ok := Code(m.key)
+		if !ok || m.integer == 0 {
+			continue
+		}
+		out = append(out, Preference{m.key, code, int(m.integer)})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Weight != out[j].Weight {
+			return out[i].Weight > out[j].Weight
+		}
+		return strength(out[i].Code) < strength(out[j].Code)
+	})
+	return out, nil
+}
+
+// FormatWant formats preferences as a Want-Content-Digest or Want-Repr-Digest
+// field value.
+func FormatWant(prefs ...Preference) string {
+	parts := make([]string, 0, len(prefs))
+	for _, p := range prefs {
+		parts = append(parts, fmt.Sprintf("%s=%d", p.Algorithm, p.Weight))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Verify checks data against a Content-Digest or Repr-Digest field value,
+// using the strongest supported algorithm it contains.
+func Verify(data []byte, value string) error {
+	mhs, err := ParseDigest(value)
+	if err != nil {
+		return err
+	}
+	expected, err := strongest(mhs)
+	if err != nil {
+		return err
+	}
+	dm, err := mh.Decode(expected)
+	if err != nil {
+		return err
+	}
+
+	actual, err := mh.Sum(data, dm.Code, -1)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actual, expected) {
+		name, _ := Algorithm(dm.Code)
+		adm, _ := mh.Decode(actual)
+		return ErrMismatch{name, dm.Digest, adm.Digest}
+	}
+	return nil
+}
+
+// strongest returns the multihash of the strongest registered algorithm.
+func strongest(mhs []mh.Multihash) (mh.Multihash, error) {
+	var best mh.Multihash
+	bestStrength := len(algorithms)
+	for _, m := range mhs {
+		dm, err := mh.Decode(m)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := mh.GetHasher(dm.Code); err != nil {
+			continue
+		}
+		if s := strength(dm.Code); s < bestStrength {
+			best, bestStrength = m, s
+		}
+	}
+	if best == nil {
+		return nil, ErrNoDigest
+	}
+	return best, nil
+}
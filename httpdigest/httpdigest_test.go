@@ -0,0 +1,300 @@
+package httpdigest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Example from RFC 9530.
+var (
+	exampleBody   = []byte(`{"hello": "world"}`)
+	exampleSHA256 = "sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:"
+	exampleSHA512 = "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:"
+)
+
+func TestDigestRoundTrip(t *testing.T) {
+	value := exampleSHA256 + ", " + exampleSHA512
+	mhs, err := ParseDigest(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 2 {
+		t.Fatalf("expected 2 digests, got %d", len(mhs))
+	}
+
+	for i, code := range []uint64{mh.SHA2_256, mh.SHA2_512} {
+		expected, err := mh.Sum(exampleBody, code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mhs[i].HexString() != expected.HexString() {
+			t.Errorf("expected %s, got %s", expected, mhs[i])
+		}
+	}
+
+	s, err := FormatDigest(mhs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != value {
+		t.Errorf("expected %q, got %q", value, s)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	mhs, err := ParseDigest(`unixsum=:AAA=:;foo=bar, sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:;x="y", crc32c=(1 2), adler`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 1 {
+		t.Fatalf("expected only the sha-256 digest, got %d", len(mhs))
+	}
+
+	mhs, err = ParseDigest(exampleSHA512 + ", " + exampleSHA256 + ", " + exampleSHA512)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 2 {
+		t.Fatalf("expected the repeated sha-512 digest once, got %d digests", len(mhs))
+	}
+
+	for _, s := range []string{
+		"sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=",
+		"sha-256=:AAAA:",
+		"sha-256=42",
+		"sha-256=:!!:",
+		"sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:,",
+		"SHA-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:",
+	} {
+		if _, err := ParseDigest(s); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected ErrSyntax, got %v", s, err)
+		}
+	}
+}
+
+func TestFormatDigestErrors(t *testing.T) {
+	trunc, err := mh.Sum(exampleBody, mh.SHA2_256, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FormatDigest(trunc); err == nil {
+		t.Error("expected truncated multihash to fail")
+	}
+
+	sha3, err := mh.Sum(exampleBody, mh.SHA3_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FormatDigest(sha3); err == nil {
+		t.Error("expected sha3-256 to fail")
+	}
+}
+
+func TestParseWant(t *testing.T) {
+	prefs, err := ParseWant("sha-256=5, unixsum=8, md5=0, sha-512=5, sha=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Preference{
+		{"sha", mh.SHA1, 10},
+		{"sha-512", mh.SHA2_512, 5},
+		{"sha-256", mh.SHA2_256, 5},
+	}
+	if len(prefs) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, prefs)
+	}
+	for i := range prefs {
+		if prefs[i] != expected[i] {
+			t.Errorf("%d: expected %v, got %v", i, expected[i], prefs[i])
+		}
+	}
+
+	if s := FormatWant(expected...); s != "sha=10, sha-512=5, sha-256=5" {
+		t.Errorf("unexpected FormatWant result %q", s)
+	}
+
+	for _, s := range []string{"sha-256=11", "sha-256=-1", "sha-256=:AAAA:", "sha-256"} {
+		if _, err := ParseWant(s); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%q: expected ErrSyntax, got %v", s, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := Verify(exampleBody, exampleSHA256+", "+exampleSHA512); err != nil {
+		t.Error(err)
+	}
+	if err := Verify([]byte("other"), exampleSHA256); !errors.Is(err, mh.ErrDigestMismatch) {
+		t.Errorf("expected a mismatch, got %v", err)
+	}
+	if err := Verify(exampleBody, "unixsum=:AAA=:"); !errors.Is(err, ErrNoDigest) {
+		t.Errorf("expected ErrNoDigest, got %v", err)
+	}
+
+	// A repeated key overrides the earlier one (RFC 8941).
+	zeros := "sha-256=:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=:"
+	if err := Verify(exampleBody, zeros+", "+exampleSHA256); err != nil {
+		t.Error(err)
+	}
+	if err := Verify(exampleBody, exampleSHA256+", "+zeros); !errors.Is(err, mh.ErrDigestMismatch) {
+		t.Errorf("expected the last sha-256 to be used, got %v", err)
+	}
+}
+
+func newServer(t *testing.T, h http.Handler) *httptest.Server {
+	s := httptest.NewServer(h)
+	t.Cleanup(s.Close)
+	return s
+}
+
+func get(t *testing.T, client *http.Client, url string, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestHandler(t *testing.T) {
+	s := newServer(t, Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(exampleBody[:5])
+		w.Write(exampleBody[5:])
+	})))
+
+	resp, body, err := get(t, s.Client(), s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != string(exampleBody) {
+		t.Errorf("unexpected body %q", body)
+	}
+	if v := resp.Header.Get(ContentDigest); v != exampleSHA256 {
+		t.Errorf("expected %q, got %q", exampleSHA256, v)
+	}
+
+	resp, _, err = get(t, s.Client(), s.URL, http.Header{WantContentDigest: {"sha-256=3, sha-512=10"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := resp.Header.Get(ContentDigest); v != exampleSHA512 {
+		t.Errorf("expected %q, got %q", exampleSHA512, v)
+	}
+}
+
+func TestHandlerStatus(t *testing.T) {
+	s := newServer(t, Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(exampleBody)
+	}), mh.SHA2_256, mh.SHA2_512))
+
+	resp, _, err := get(t, s.Client(), s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if v := resp.Header.Get(ContentDigest); v != exampleSHA256+", "+exampleSHA512 {
+		t.Errorf("unexpected %s %q", ContentDigest, v)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var gotWant string
+	good := newServer(t, Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotWant = r.Header.Get(WantContentDigest)
+		w.Write(exampleBody)
+	})))
+	bad := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentDigest, exampleSHA256)
+		w.Write([]byte("tampered"))
+	}))
+	none := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(exampleBody)
+	}))
+
+	client := &http.Client{Transport: &Transport{Base: good.Client().Transport, Want: "sha-512=10"}}
+
+	resp, body, err := get(t, client, good.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != string(exampleBody) {
+		t.Errorf("unexpected body %q", body)
+	}
+	if gotWant != "sha-512=10" {
+		t.Errorf("server got %s %q", WantContentDigest, gotWant)
+	}
+	if v := resp.Header.Get(ContentDigest); v != exampleSHA512 {
+		t.Errorf("unexpected %s %q", ContentDigest, v)
+	}
+
+	_, _, err = get(t, client, bad.URL, nil)
+	var mismatch ErrMismatch
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected ErrMismatch, got %v", err)
+	}
+	if mismatch.Algorithm != "sha-256" || !errors.Is(err, mh.ErrDigestMismatch) {
+		t.Errorf("unexpected mismatch %v", mismatch)
+	}
+
+	if _, _, err := get(t, client, none.URL, nil); err != nil {
+		t.Errorf("unexpected error without a digest: %s", err)
+	}
+
+	strict := &http.Client{Transport: &Transport{Require: true}}
+	if _, _, err := get(t, strict, none.URL, nil); !errors.Is(err, ErrNoDigest) {
+		t.Errorf("expected ErrNoDigest, got %v", err)
+	}
+}
+
+func TestTransportNoContent(t *testing.T) {
+	// The field describes the content of a GET, which these responses omit.
+	s := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentDigest, exampleSHA256)
+		switch r.URL.Path {
+		case "/204":
+			w.WriteHeader(http.StatusNoContent)
+		case "/304":
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Write(exampleBody)
+		}
+	}))
+	client := &http.Client{Transport: &Transport{Base: s.Client().Transport, Require: true}}
+
+	resp, err := client.Head(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, err := io.ReadAll(resp.Body); err != nil || len(body) != 0 {
+		t.Errorf("HEAD: unexpected body %q and error %v", body, err)
+	}
+
+	for _, path := range []string{"/204", "/304"} {
+		resp, body, err := get(t, client, s.URL+path, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", path, err)
+		} else if len(body) != 0 {
+			t.Errorf("%s: unexpected body %q", path, body)
+		} else if resp.Header.Get(ContentDigest) != exampleSHA256 {
+			t.Errorf("%s: %s field was dropped", path, ContentDigest)
+		}
+	}
+}
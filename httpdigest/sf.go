@@ -0,0 +1,245 @@
+package httpdigest
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrSyntax is returned when a field value is not a valid structured field
+// dictionary (RFC 8941).
+var ErrSyntax = errors.New("invalid structured field dictionary")
+
+// member is a single dictionary member. Only the value kinds used by
+// RFC 9530 are decoded; anything else is skipped and reported as kindOther.
+type member struct {
+	key     string
+	kind    itemKind
+	bytes   []byte
+	integer int64
+}
+
+type itemKind int
+
+const (
+	kindOther itemKind = iota
+	kindBytes
+	kindInteger
+)
+
+// parseDictionary parses a structured field dictionary. Parameters and inner
+// lists are accepted but ignored. As RFC 8941 requires, a repeated key
+// overwrites the value of the earlier one, which keeps its position.
+func parseDictionary(s string) ([]member, error) {
+	p := sfParser{s: s}
+	var out []member
+	index := map[string]int{}
+
+	p.skipSP()
+	if p.done() {
+		return nil, nil
+	}
+	for {
+		m, err := p.member()
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := index[m.key]; ok {
+			out[i] = m
+		} else {
+			index[m.key] = len(out)
+			out = append(out, m)
+		}
+
+		p.skipOWS()
+		if p.done() {
+			return out, nil
+		}
+		if p.next() != ',' {
+			return nil, p.errorf("expected ','")
+		}
+		p.skipOWS()
+		if p.done() {
+			return nil, p.errorf("trailing ','")
+		}
+	}
+}
+
+type sfParser struct {
+	s string
+	i int
+}
+
+func (p *sfParser) done() bool { return p.i >= len(p.s) }
+
+func (p *sfParser) peek() byte {
+	if p.done() {
+		return 0
+	}
+	return p.s[p.i]
+}
+
+func (p *sfParser) next() byte {
+	c := p.peek()
+	p.i++
+	return c
+}
+
+func (p *sfParser) skipSP() {
+	for p.peek() == ' ' {
+		p.i++
+	}
+}
+
+func (p *sfParser) skipOWS() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.i++
+	}
+}
+
+func (p *sfParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrSyntax, fmt.Sprintf(format, args...), p.i)
+}
+
+func (p *sfParser) member() (member, error) {
+	key, err := p.key()
+	if err != nil {
+		return member{}, err
+	}
+	m := member{key: key}
+
+	if p.peek() != '=' {
+		// Boolean true, with optional parameters.
+		return m, p.params()
+	}
+	p.i++
+
+	if p.peek() == '(' {
+		if err := p.innerList(); err != nil {
+			return member{}, err
+		}
+	} else if err := p.bareItem(&m); err != nil {
+		return member{}, err
+	}
+	return m, p.params()
+}
+
+func (p *sfParser) key() (string, error) {
+	start := p.i
+	if c := p.peek(); !(c >= 'a' && c <= 'z') && c != '*' {
+		return "", p.errorf("expected key")
+	}
+	for {
+		c := p.peek()
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' || c == '-' || c == '.' || c == '*' {
+			p.i++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.i], nil
+}
+
+func (p *sfParser) params() error {
+	for p.peek() == ';' {
+		p.i++
+		p.skipSP()
+		if _, err := p.key(); err != nil {
+			return err
+		}
+		if p.peek() == '=' {
+			p.i++
+			if err := p.bareItem(&member{}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *sfParser) innerList() error {
+	p.i++ // '('
+	for {
+		p.skipSP()
+		if p.peek() == ')' {
+			p.i++
+			return nil
+		}
+		if p.done() {
+			return p.errorf("unterminated inner list")
+		}
+		if err := p.bareItem(&member{}); err != nil {
+			return err
+		}
+		if err := p.params(); err != nil {
+			return err
+		}
+		if c := p.peek(); c != ' ' && c != ')' {
+			return p.errorf("expected ' ' or ')'")
+		}
+	}
+}
+
+func (p *sfParser) bareItem(m *member) error {
+	switch c := p.peek(); {
+	case c == ':':
+		p.i++
+		end := strings.IndexByte(p.s[p.i:], ':')
+		if end < 0 {
+			return p.errorf("unterminated byte sequence")
+		}
+		b, err := base64.StdEncoding.DecodeString(p.s[p.i : p.i+end])
+		if err != nil {
+			return p.errorf("bad byte sequence: %s", err)
+		}
+		p.i += end + 1
+		m.kind, m.bytes = kindBytes, b
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.i
+		p.i++
+		for c := p.peek(); (c >= '0' && c <= '9') || c == '.'; c = p.peek() {
+			p.i++
+		}
+		v, err := strconv.ParseInt(p.s[start:p.i], 10, 64)
+		if err == nil {
+			m.kind, m.integer = kindInteger, v
+		} else if _, err := strconv.ParseFloat(p.s[start:p.i], 64); err != nil {
+			return p.errorf("bad number")
+		}
+	case c == '"':
+		p.i++
+		for {
+			switch p.next() {
+			case '\\':
+				p.i++
+			case '"':
+				return nil
+			case 0:
+				return p.errorf("unterminated string")
+			}
+		}
+	case c == '?':
+		p.i++
+		if c := p.next(); c != '0' && c != '1' {
+			return p.errorf("bad boolean")
+		}
+	case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '*':
+		for {
+			c := p.peek()
+			if c > ' ' && c < 0x7f && !strings.ContainsRune(`"(),;<=>?@[\]{}`, rune(c)) {
+				p.i++
+				continue
+			}
+			break
+		}
+	default:
+		return p.errorf("unexpected %q", c)
+	}
+	return nil
+}
+
+func encodeBytes(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
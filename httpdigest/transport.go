@@ -0,0 +1,127 @@
+package httpdigest
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Transport is an http.RoundTripper which verifies the Content-Digest field of
+// responses.
+//
+// The response body is checked while it is read: once the end of the body is
+// reached, reading returns an ErrMismatch error instead of io.EOF if the
+// content does not match the strongest supported digest in the field.
+// Responses to HEAD requests, and 204 and 304 responses, have no content and
+// are not checked.
+type Transport struct {
+	// Base is the RoundTripper used to make requests.
+	// If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Want, if set, is sent as the Want-Content-Digest field of requests
+	// which do not already have one, e.g. "sha-512=10, sha-256=5".
+	Want string
+
+	// Require makes responses without a verifiable Content-Digest field fail
+	// with ErrNoDigest instead of being passed through unchecked.
+	Require bool
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Want != "" && req.Header.Get(WantContentDigest) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(WantContentDigest, t.Want)
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Method == http.MethodHead || !hasContent(resp.StatusCode) {
+		// The field describes the content a GET would have returned, which
+		// is not in the response.
+		return resp, nil
+	}
+
+	expected, err := t.expected(resp)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if expected == nil {
+		return resp, nil
+	}
+
+	dm, err := mh.Decode(expected)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	hasher, err := mh.GetHasher(dm.Code)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &verifyingBody{ReadCloser: resp.Body, hasher: hasher, code: dm.Code, expected: dm.Digest}
+	return resp, nil
+}
+
+// expected returns the digest to verify the response against, or nil if the
+// response is passed through unchecked.
+func (t *Transport) expected(resp *http.Response) (mh.Multihash, error) {
+	value := resp.Header.Get(ContentDigest)
+	if value == "" || resp.Uncompressed {
+		// The digest of transparently decompressed content cannot be checked.
+		if t.Require {
+			return nil, fmt.Errorf("%w in %s field", ErrNoDigest, ContentDigest)
+		}
+		return nil, nil
+	}
+
+	mhs, err := ParseDigest(value)
+	if err != nil {
+		return nil, err
+	}
+	m, err := strongest(mhs)
+	if err == ErrNoDigest && !t.Require {
+		return nil, nil
+	}
+	return m, err
+}
+
+type verifyingBody struct {
+	io.ReadCloser
+	hasher   hash.Hash
+	code     uint64
+	expected []byte
+	err      error
+}
+
+func (b *verifyingBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.hasher.Write(p[:n])
+	if err == io.EOF {
+		if actual := b.hasher.Sum(nil); !bytes.Equal(actual, b.expected) {
+			name, _ := Algorithm(b.code)
+			b.err = ErrMismatch{name, b.expected, actual}
+			return n, b.err
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,62 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+
+	mhreg "github.com/multiformats/go-multihash/core"
+)
+
+// CandidatesForLength returns, in ascending order, the codes of the
+// registered hash functions which can produce an n byte digest, either
+// natively, by truncation, or as a variable-size output.
+//
+// The identity code is not included: it is not a hash function.
+func CandidatesForLength(n int) []uint64 {
+	if n <= 0 {
+		return nil
+	}
+
+	var codes []uint64
+	for code := range DefaultLengths {
+		if code == IDENTITY {
+			continue
+		}
+		h, err := mhreg.GetVariableHasher(code, n)
+		if err != nil || h.Size() < n {
+			continue
+		}
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Identify finds which registered hash functions, at the length of
+// rawDigest, turn data into rawDigest. It returns the matching multihashes,
+// ordered by code, or none if nothing matches.
+//
+// Every candidate of CandidatesForLength is tried, which hashes data once per
+// candidate; prefer a small sample.
+func Identify(data, rawDigest []byte) ([]Multihash, error) {
+	if len(rawDigest) == 0 {
+		return nil, errors.New("cannot identify an empty digest")
+	}
+
+	var matches []Multihash
+	for _, code := range CandidatesForLength(len(rawDigest)) {
+		m, err := Sum(data, code, len(rawDigest))
+		if err != nil {
+			continue
+		}
+		dm, err := decode(m)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(dm.Digest, rawDigest) {
+			matches = append(matches, m)
+		}
+	}
+	return matches, nil
+}
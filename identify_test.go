@@ -0,0 +1,85 @@
+package multihash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCandidatesForLength(t *testing.T) {
+	candidates := CandidatesForLength(32)
+	has := func(code uint64) bool {
+		for _, c := range candidates {
+			if c == code {
+				return true
+			}
+		}
+		return false
+	}
+	for _, code := range []uint64{SHA2_256, SHA2_512, SHA3_256, BLAKE3, BLAKE2B_MIN + 31} {
+		if !has(code) {
+			t.Errorf("%s should be a candidate for 32 bytes", Codes[code])
+		}
+	}
+	for _, code := range []uint64{IDENTITY, SHA1, MD5, BLAKE2B_MIN + 19} {
+		if has(code) {
+			t.Errorf("%s should not be a candidate for 32 bytes", Codes[code])
+		}
+	}
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i-1] >= candidates[i] {
+			t.Fatal("candidates are not sorted")
+		}
+	}
+
+	if len(CandidatesForLength(0)) != 0 {
+		t.Error("no hash function produces empty digests")
+	}
+	if len(CandidatesForLength(1000)) != 0 {
+		t.Error("no registered hash function produces 1000 byte digests")
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	data := []byte("legacy sample")
+	for _, tc := range []struct {
+		code   uint64
+		length int
+	}{
+		{SHA1, -1},
+		{MD5, -1},
+		{SHA2_256, -1},
+		{SHA2_256, 16},
+		{SHA3_512, -1},
+		{BLAKE3, 24},
+		{BLAKE2S_MIN + 31, -1},
+	} {
+		m, err := Sum(data, tc.code, tc.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm, err := Decode(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		matches, err := Identify(data, dm.Digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(matches) != 1 || !bytes.Equal(matches[0], m) {
+			t.Errorf("%s/%d: expected a single match, got %v", dm.Name, dm.Length, matches)
+		}
+	}
+
+	matches, err := Identify(data, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("expected no match, got %v", matches)
+	}
+
+	if _, err := Identify(data, nil); err == nil {
+		t.Error("expected an error for an empty digest")
+	}
+}
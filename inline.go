@@ -0,0 +1,32 @@
+package multihash
+
+import "errors"
+
+// DefaultMaxIdentitySize is the largest identity multihash digest, in bytes,
+// that the IPFS ecosystem accepts in CIDs. Data larger than this should be
+// hashed rather than inlined.
+const DefaultMaxIdentitySize = 128
+
+// ErrIdentityTooLarge is returned when an identity multihash exceeds a size
+// limit, such as the one set with WithMaxIdentitySize.
+var ErrIdentityTooLarge = errors.New("identity multihash too large")
+
+// SumOrInline returns an identity multihash holding data if it is at most
+// maxInline bytes long, and the multihash of data with the given code
+// otherwise. Use DefaultMaxIdentitySize for the usual limit.
+func SumOrInline(data []byte, code uint64, maxInline int) (Multihash, error) {
+	if len(data) <= maxInline {
+		return Encode(data, IDENTITY)
+	}
+	return Sum(data, code, -1)
+}
+
+// InlineData returns the data inlined in an identity multihash. It returns
+// false if m is not a valid identity multihash.
+func (m Multihash) InlineData() ([]byte, bool) {
+	dm, err := decode(m)
+	if err != nil || dm.Code != IDENTITY {
+		return nil, false
+	}
+	return dm.Digest, true
+}
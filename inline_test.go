@@ -0,0 +1,49 @@
+package multihash
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSumOrInline(t *testing.T) {
+	small := []byte("hello")
+	m, err := SumOrInline(small, SHA2_256, DefaultMaxIdentitySize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.HexString() != "000568656c6c6f" {
+		t.Errorf("expected an identity multihash, got %s", m.HexString())
+	}
+	data, ok := m.InlineData()
+	if !ok || !bytes.Equal(data, small) {
+		t.Errorf("expected inline data %q, got %q (%v)", small, data, ok)
+	}
+
+	// Data exactly at the limit is still inlined.
+	m, err = SumOrInline(small, SHA2_256, len(small))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.InlineData(); !ok {
+		t.Error("data at the limit should be inlined")
+	}
+
+	m, err = SumOrInline(small, SHA2_256, len(small)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := Sum(small, SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m, expected) {
+		t.Errorf("expected %s, got %s", expected.HexString(), m.HexString())
+	}
+	if _, ok := m.InlineData(); ok {
+		t.Error("a sha2-256 multihash has no inline data")
+	}
+
+	if _, ok := Multihash([]byte{0x00, 0x05, 'h'}).InlineData(); ok {
+		t.Error("an invalid multihash has no inline data")
+	}
+}
@@ -0,0 +1,187 @@
+// Package keccak implements the Keccak-p[1600] permutation and a sponge on
+// top of it, for the Keccak-based hash functions that golang.org/x/crypto
+// does not expose: legacy Keccak at every size, and reduced-round variants
+// such as TurboSHAKE.
+package keccak
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Rounds is the number of rounds of Keccak-f[1600], the full permutation.
+const Rounds = 24
+
+// roundConstants are the iota constants of the 24 rounds of Keccak-f[1600].
+var roundConstants = [Rounds]uint64{
+	0x0000000000000001, 0x0000000000008082, 0x800000000000808a, 0x8000000080008000,
+	0x000000000000808b, 0x0000000080000001, 0x8000000080008081, 0x8000000000008009,
+	0x000000000000008a, 0x0000000000000088, 0x0000000080008009, 0x000000008000000a,
+	0x000000008000808b, 0x800000000000008b, 0x8000000000008089, 0x8000000000008003,
+	0x8000000000008002, 0x8000000000000080, 0x000000000000800a, 0x800000008000000a,
+	0x8000000080008081, 0x8000000000008080, 0x0000000080000001, 0x8000000080008008,
+}
+
+// rotations are the rho offsets, indexed by x + 5y.
+var rotations = [25]int{
+	0, 1, 62, 28, 27,
+	36, 44, 6, 55, 20,
+	3, 10, 43, 25, 39,
+	41, 45, 15, 21, 8,
+	18, 2, 61, 56, 14,
+}
+
+// Permute applies Keccak-p[1600, rounds] to the state, that is the last
+// rounds rounds of Keccak-f[1600].
+func Permute(a *[25]uint64, rounds int) {
+	var c, d [5]uint64
+	var b [25]uint64
+	for _, rc := range roundConstants[Rounds-rounds:] {
+		// theta
+		for x := 0; x < 5; x++ {
+			c[x] = a[x] ^ a[x+5] ^ a[x+10] ^ a[x+15] ^ a[x+20]
+		}
+		for x := 0; x < 5; x++ {
+			d[x] = c[(x+4)%5] ^ bits.RotateLeft64(c[(x+1)%5], 1)
+		}
+		for i := range a {
+			a[i] ^= d[i%5]
+		}
+		// rho and pi
+		for x := 0; x < 5; x++ {
+			for y := 0; y < 5; y++ {
+				b[y+5*((2*x+3*y)%5)] = bits.RotateLeft64(a[x+5*y], rotations[x+5*y])
+			}
+		}
+		// chi
+		for y := 0; y < 25; y += 5 {
+			for x := 0; x < 5; x++ {
+				a[x+y] = b[x+y] ^ (^b[(x+1)%5+y] & b[(x+2)%5+y])
+			}
+		}
+		// iota
+		a[0] ^= rc
+	}
+}
+
+// Sponge is a Keccak sponge absorbing into, and squeezing from, a
+// Keccak-p[1600] state. It implements hash.Hash, with Sum returning the
+// first Size bytes of output, and can be read from as an extendable-output
+// function once absorbing is done.
+type Sponge struct {
+	a      [25]uint64
+	buf    [200]byte
+	n      int // bytes buffered while absorbing, or left to read while squeezing
+	rate   int
+	dsbyte byte
+	rounds int
+	size   int
+
+	squeezing bool
+}
+
+var _ hash.Hash = (*Sponge)(nil)
+
+// New returns a sponge with the given rate in bytes, domain separation byte
+// (the message suffix bits followed by the first padding bit, e.g. 0x01 for
+// legacy Keccak and 0x06 for SHA-3), number of rounds and output size.
+func New(rate int, dsbyte byte, rounds, size int) *Sponge {
+	if rate <= 0 || rate >= 200 || rate%8 != 0 {
+		panic("keccak: invalid rate")
+	}
+	return &Sponge{rate: rate, dsbyte: dsbyte, rounds: rounds, size: size}
+}
+
+// NewLegacyKeccak returns legacy Keccak, as used before SHA-3 was
+// standardised, with a digest of size bytes and a capacity of twice that.
+func NewLegacyKeccak(size int) *Sponge {
+	return New(200-2*size, 0x01, Rounds, size)
+}
+
+// Size returns the number of bytes Sum returns.
+func (s *Sponge) Size() int { return s.size }
+
+// BlockSize returns the rate of the sponge.
+func (s *Sponge) BlockSize() int { return s.rate }
+
+// Reset clears the sponge.
+func (s *Sponge) Reset() {
+	s.a = [25]uint64{}
+	s.n = 0
+	s.squeezing = false
+}
+
+// Write absorbs data. It panics once reading has started.
+func (s *Sponge) Write(p []byte) (int, error) {
+	if s.squeezing {
+		panic("keccak: write after read")
+	}
+	written := len(p)
+	for len(p) > 0 {
+		k := copy(s.buf[s.n:s.rate], p)
+		s.n += k
+		p = p[k:]
+		if s.n == s.rate {
+			s.absorb()
+		}
+	}
+	return written, nil
+}
+
+func (s *Sponge) absorb() {
+	for i := 0; i < s.rate/8; i++ {
+		s.a[i] ^= binary.LittleEndian.Uint64(s.buf[8*i:])
+	}
+	Permute(&s.a, s.rounds)
+	s.n = 0
+}
+
+// pad finishes absorbing and switches to squeezing.
+func (s *Sponge) pad() {
+	for i := s.n; i < s.rate; i++ {
+		s.buf[i] = 0
+	}
+	s.buf[s.n] ^= s.dsbyte
+	s.buf[s.rate-1] ^= 0x80
+	s.absorb()
+	s.squeeze()
+	s.squeezing = true
+}
+
+func (s *Sponge) squeeze() {
+	for i := 0; i < s.rate/8; i++ {
+		binary.LittleEndian.PutUint64(s.buf[8*i:], s.a[i])
+	}
+	s.n = s.rate
+}
+
+// Read squeezes output. The first call ends absorbing.
+func (s *Sponge) Read(p []byte) (int, error) {
+	if !s.squeezing {
+		s.pad()
+	}
+	read := len(p)
+	for len(p) > 0 {
+		if s.n == 0 {
+			Permute(&s.a, s.rounds)
+			s.squeeze()
+		}
+		k := copy(p, s.buf[s.rate-s.n:s.rate])
+		s.n -= k
+		p = p[k:]
+	}
+	return read, nil
+}
+
+// Sum appends the first Size bytes of output to b without changing the
+// state of the sponge.
+func (s *Sponge) Sum(b []byte) []byte {
+	if s.squeezing {
+		panic("keccak: sum after read")
+	}
+	dup := *s
+	out := make([]byte, s.size)
+	dup.Read(out)
+	return append(b, out...)
+}
@@ -0,0 +1,107 @@
+package keccak
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Vectors from the Keccak team's ShortMsgKAT files for the final (round 3)
+// submission, which predate the SHA-3 padding change.
+var katVectors = []struct {
+	size    int
+	message string
+	digest  string
+}{
+	{28, "", "f71837502ba8e10837bdd8d365adb85591895602fc552b48b7390abd"},
+	{28, "cc", "a9cab59eb40a10b246290f2d6086e32e3689faf1d26b470c899f2802"},
+	{28, "41fb", "615ba367afdc35aac397bc7eb5d58d106a734b24986d5d978fefd62c"},
+	{48, "", "2c23146a63a29acf99e73b88f8c24eaa7dc60aa771780ccc006afbfa8fe2479b2dd2b21362337441ac12b515911957ff"},
+	{48, "cc", "1b84e62a46e5a201861754af5dc95c4a1a69caf4a796ae405680161e29572641f5fa1e8641d7958336ee7b11c58f73e9"},
+	{48, "41fb", "495cce2714cd72c8c53c3363d22c58b55960fe26be0bf3bbc7a3316dd563ad1db8410e75eefea655e39d4670ec0b1792"},
+	{32, "cc", "eead6dbfc7340a56caedc044696a168870549a6a7f6f56961e84a54bd9970b8a"},
+	{64, "cc", "8630c13cbd066ea74bbe7fe468fec1dee10edc1254fb4c1b7c5fd69b646e44160b8ce01d05a0908ca790dfb080f4b513bc3b6225ece7a810371441a5ac666eb9"},
+}
+
+func TestLegacyKeccakKAT(t *testing.T) {
+	for _, v := range katVectors {
+		msg, err := hex.DecodeString(v.message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := NewLegacyKeccak(v.size)
+		h.Write(msg)
+		if got := hex.EncodeToString(h.Sum(nil)); got != v.digest {
+			t.Errorf("keccak-%d(%q): expected %s, got %s", v.size*8, v.message, v.digest, got)
+		}
+	}
+}
+
+// TestAgainstXCrypto compares the sponge with the x/crypto implementations
+// for every message length across a few blocks.
+func TestAgainstXCrypto(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ref  func() hash.Hash
+		ours func() hash.Hash
+	}{
+		{"keccak-256", sha3.NewLegacyKeccak256, func() hash.Hash { return NewLegacyKeccak(32) }},
+		{"keccak-512", sha3.NewLegacyKeccak512, func() hash.Hash { return NewLegacyKeccak(64) }},
+		{"sha3-224", sha3.New224, func() hash.Hash { return New(144, 0x06, Rounds, 28) }},
+		{"sha3-384", sha3.New384, func() hash.Hash { return New(104, 0x06, Rounds, 48) }},
+	} {
+		data := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog ", 10))
+		for n := 0; n <= len(data); n++ {
+			ref, ours := tc.ref(), tc.ours()
+			ref.Write(data[:n])
+			// Write in two pieces to exercise buffering.
+			ours.Write(data[:n/3])
+			ours.Write(data[n/3 : n])
+			if !bytes.Equal(ref.Sum(nil), ours.Sum(nil)) {
+				t.Fatalf("%s: mismatch for a %d byte message", tc.name, n)
+			}
+		}
+	}
+}
+
+func TestShakeRead(t *testing.T) {
+	ref := sha3.NewShake128()
+	ref.Write([]byte("abc"))
+	expected := make([]byte, 500)
+	ref.Read(expected)
+
+	ours := New(168, 0x1f, Rounds, 32)
+	ours.Write([]byte("abc"))
+	out := make([]byte, 500)
+	// Read in uneven pieces, across block boundaries.
+	for i := 0; i < len(out); i += 37 {
+		ours.Read(out[i:min(i+37, len(out))])
+	}
+	if !bytes.Equal(out, expected) {
+		t.Error("shake128 output mismatch")
+	}
+}
+
+func TestSumDoesNotChangeState(t *testing.T) {
+	h := NewLegacyKeccak(28)
+	h.Write([]byte("ab"))
+	first := h.Sum(nil)
+	if !bytes.Equal(first, h.Sum(nil)) {
+		t.Error("Sum changed the state")
+	}
+	h.Write([]byte("c"))
+	h2 := NewLegacyKeccak(28)
+	h2.Write([]byte("abc"))
+	if !bytes.Equal(h.Sum(nil), h2.Sum(nil)) {
+		t.Error("writing after Sum gave a different digest")
+	}
+
+	h.Reset()
+	if !bytes.Equal(h.Sum(nil), NewLegacyKeccak(28).Sum(nil)) {
+		t.Error("Reset did not clear the state")
+	}
+}
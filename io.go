@@ -2,6 +2,7 @@ package multihash
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"math"
 
@@ -24,9 +25,26 @@ type Writer interface {
 	WriteMultihash(Multihash) error
 }
 
+// ReaderOption configures a Reader returned by NewReader.
+type ReaderOption func(*mhReader)
+
+// WithMaxIdentitySize makes ReadMultihash reject identity multihashes whose
+// digest is longer than size bytes, with an error matching
+// ErrIdentityTooLarge, before reading the digest. DefaultMaxIdentitySize is
+// the limit commonly enforced for CIDs.
+func WithMaxIdentitySize(size int) ReaderOption {
+	return func(r *mhReader) {
+		r.maxIdentitySize = size
+	}
+}
+
 // NewReader wraps an io.Reader with a multihash.Reader
-func NewReader(r io.Reader) Reader {
-	return &mhReader{r}
+func NewReader(r io.Reader, opts ...ReaderOption) Reader {
+	mr := &mhReader{r: r, maxIdentitySize: -1}
+	for _, opt := range opts {
+		opt(mr)
+	}
+	return mr
 }
 
 // NewWriter wraps an io.Writer with a multihash.Writer
@@ -36,6 +54,9 @@ func NewWriter(w io.Writer) Writer {
 
 type mhReader struct {
 	r io.Reader
+
+	// maxIdentitySize is negative when there is no limit.
+	maxIdentitySize int
 }
 
 func (r *mhReader) Read(buf []byte) (n int, err error) {
@@ -73,6 +94,9 @@ func (r *mhReader) ReadMultihash() (Multihash, error) {
 	if length > math.MaxInt32 {
 		return nil, errors.New("digest too long, supporting only <= 2^31-1")
 	}
+	if code == IDENTITY && r.maxIdentitySize >= 0 && length > uint64(r.maxIdentitySize) {
+		return nil, fmt.Errorf("%w: %d bytes, limit is %d", ErrIdentityTooLarge, length, r.maxIdentitySize)
+	}
 
 	buf := make([]byte, varint.UvarintSize(code)+varint.UvarintSize(length)+int(length))
 	n := varint.PutUvarint(buf, code)
@@ -2,6 +2,7 @@ package multihash
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"testing"
 )
@@ -100,3 +101,38 @@ func TestWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestReaderMaxIdentitySize(t *testing.T) {
+	small, err := Sum([]byte("small"), IDENTITY, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	large, err := Sum(bytes.Repeat([]byte{'x'}, DefaultMaxIdentitySize+1), IDENTITY, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(small)
+	buf.Write(large)
+	r := NewReader(&buf, WithMaxIdentitySize(DefaultMaxIdentitySize))
+	h, err := r.ReadMultihash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h, small) {
+		t.Error("small identity multihash should be read back")
+	}
+	if _, err := r.ReadMultihash(); !errors.Is(err, ErrIdentityTooLarge) {
+		t.Errorf("expected ErrIdentityTooLarge, got %v", err)
+	}
+
+	// Without the option there is no limit.
+	h, err = NewReader(bytes.NewReader(large)).ReadMultihash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(h, large) {
+		t.Error("large identity multihash should be read back")
+	}
+}
@@ -20,6 +20,7 @@ var (
 	ErrTooLong          = errors.New("multihash too long. must be < 129 bytes")
 	ErrLenNotSupported  = errors.New("multihash does not yet support digests longer than 127 bytes")
 	ErrInvalidMultihash = errors.New("input isn't valid multihash")
+	ErrDigestMismatch   = errors.New("digest does not match data")
 
 	ErrVarintBufferShort = errors.New("uvarint: buffer too small")
 	ErrVarintTooLong     = errors.New("uvarint: varint too big (max 64bit)")
@@ -42,6 +43,7 @@ const (
 	ID         = IDENTITY
 	SHA1       = 0x11
 	SHA2_256   = 0x12
+	SHA2_384   = 0x20
 	SHA2_512   = 0x13
 	SHA3_224   = 0x17
 	SHA3_256   = 0x16
@@ -54,6 +56,8 @@ const (
 	KECCAK_512 = 0x1D
 	BLAKE3     = 0x1E
 
+	KANGAROOTWELVE = 0x1d01
+
 	SHAKE_128 = 0x18
 	SHAKE_256 = 0x19
 
@@ -62,17 +66,42 @@ const (
 	BLAKE2S_MIN = 0xb241
 	BLAKE2S_MAX = 0xb260
 
+	SKEIN256_MIN  = 0xb301
+	SKEIN256_MAX  = 0xb320
+	SKEIN512_MIN  = 0xb321
+	SKEIN512_MAX  = 0xb360
+	SKEIN1024_MIN = 0xb361
+	SKEIN1024_MAX = 0xb3e0
+
+	MD4 = 0xd4
 	MD5 = 0xd5
 
+	RIPEMD_128 = 0x1052
+	RIPEMD_160 = 0x1053
+	RIPEMD_256 = 0x1054
+	RIPEMD_320 = 0x1055
+	SM3_256    = 0x534d
+
+	XXH_32   = 0xb3e1
+	XXH_64   = 0xb3e2
+	XXH3_64  = 0xb3e3
+	XXH3_128 = 0xb3e4
+
 	DBL_SHA2_256 = 0x56
 
 	MURMUR3X64_64 = 0x22
 	// Deprecated: use MURMUR3X64_64
 	MURMUR3 = MURMUR3X64_64
 
+	MURMUR3_32     = 0x23
+	MURMUR3X64_128 = 0x1022
+
 	SHA2_256_TRUNC254_PADDED  = 0x1012
 	X11                       = 0x1100
-	POSEIDON_BLS12_381_A1_FC1 = 0xb401
+	POSEIDON_BLS12_381_A2_FC1 = 0xb401
+	// Deprecated: use POSEIDON_BLS12_381_A2_FC1, which matches the name in
+	// the multicodec table.
+	POSEIDON_BLS12_381_A1_FC1 = POSEIDON_BLS12_381_A2_FC1
 )
 
 func init() {
@@ -91,6 +120,23 @@ func init() {
 		Names[name] = c
 		Codes[c] = name
 	}
+
+	// Add skein256 (32 codes), skein512 (64 codes) and skein1024 (128 codes)
+	for _, family := range []struct {
+		min, max uint64
+		bits     int
+	}{
+		{SKEIN256_MIN, SKEIN256_MAX, 256},
+		{SKEIN512_MIN, SKEIN512_MAX, 512},
+		{SKEIN1024_MIN, SKEIN1024_MAX, 1024},
+	} {
+		for c := family.min; c <= family.max; c++ {
+			n := c - family.min + 1
+			name := fmt.Sprintf("skein%d-%d", family.bits, n*8)
+			Names[name] = c
+			Codes[c] = name
+		}
+	}
 }
 
 // Names maps the name of a hash to the code
@@ -98,6 +144,7 @@ var Names = map[string]uint64{
 	"identity":                  IDENTITY,
 	"sha1":                      SHA1,
 	"sha2-256":                  SHA2_256,
+	"sha2-384":                  SHA2_384,
 	"sha2-512":                  SHA2_512,
 	"sha3":                      SHA3_512,
 	"sha3-224":                  SHA3_224,
@@ -105,18 +152,31 @@ var Names = map[string]uint64{
 	"sha3-384":                  SHA3_384,
 	"sha3-512":                  SHA3_512,
 	"dbl-sha2-256":              DBL_SHA2_256,
+	"murmur3-32":                MURMUR3_32,
 	"murmur3-x64-64":            MURMUR3X64_64,
+	"murmur3-x64-128":           MURMUR3X64_128,
 	"keccak-224":                KECCAK_224,
 	"keccak-256":                KECCAK_256,
 	"keccak-384":                KECCAK_384,
 	"keccak-512":                KECCAK_512,
 	"blake3":                    BLAKE3,
+	"kangarootwelve":            KANGAROOTWELVE,
 	"shake-128":                 SHAKE_128,
 	"shake-256":                 SHAKE_256,
 	"sha2-256-trunc254-padded":  SHA2_256_TRUNC254_PADDED,
 	"x11":                       X11,
+	"md4":                       MD4,
 	"md5":                       MD5,
-	"poseidon-bls12_381-a2-fc1": POSEIDON_BLS12_381_A1_FC1,
+	"ripemd-128":                RIPEMD_128,
+	"ripemd-160":                RIPEMD_160,
+	"ripemd-256":                RIPEMD_256,
+	"ripemd-320":                RIPEMD_320,
+	"sm3-256":                   SM3_256,
+	"xxh-32":                    XXH_32,
+	"xxh-64":                    XXH_64,
+	"xxh3-64":                   XXH3_64,
+	"xxh3-128":                  XXH3_128,
+	"poseidon-bls12_381-a2-fc1": POSEIDON_BLS12_381_A2_FC1,
 }
 
 // Codes maps a hash code to it's name
@@ -124,24 +184,38 @@ var Codes = map[uint64]string{
 	IDENTITY:                  "identity",
 	SHA1:                      "sha1",
 	SHA2_256:                  "sha2-256",
+	SHA2_384:                  "sha2-384",
 	SHA2_512:                  "sha2-512",
 	SHA3_224:                  "sha3-224",
 	SHA3_256:                  "sha3-256",
 	SHA3_384:                  "sha3-384",
 	SHA3_512:                  "sha3-512",
 	DBL_SHA2_256:              "dbl-sha2-256",
+	MURMUR3_32:                "murmur3-32",
 	MURMUR3X64_64:             "murmur3-x64-64",
+	MURMUR3X64_128:            "murmur3-x64-128",
 	KECCAK_224:                "keccak-224",
 	KECCAK_256:                "keccak-256",
 	KECCAK_384:                "keccak-384",
 	KECCAK_512:                "keccak-512",
 	BLAKE3:                    "blake3",
+	KANGAROOTWELVE:            "kangarootwelve",
 	SHAKE_128:                 "shake-128",
 	SHAKE_256:                 "shake-256",
 	SHA2_256_TRUNC254_PADDED:  "sha2-256-trunc254-padded",
 	X11:                       "x11",
-	POSEIDON_BLS12_381_A1_FC1: "poseidon-bls12_381-a2-fc1",
+	POSEIDON_BLS12_381_A2_FC1: "poseidon-bls12_381-a2-fc1",
+	MD4:                       "md4",
 	MD5:                       "md5",
+	RIPEMD_128:                "ripemd-128",
+	RIPEMD_160:                "ripemd-160",
+	RIPEMD_256:                "ripemd-256",
+	RIPEMD_320:                "ripemd-320",
+	SM3_256:                   "sm3-256",
+	XXH_32:                    "xxh-32",
+	XXH_64:                    "xxh-64",
+	XXH3_64:                   "xxh3-64",
+	XXH3_128:                  "xxh3-128",
 }
 
 // reads a varint from buf and returns bytes read.
@@ -23,6 +23,7 @@ Options:
 var opts *mhopts.Options
 var checkRaw string
 var checkMh mh.Multihash
+var inspectRaw string
 var quiet bool
 var help bool
 
@@ -34,10 +35,14 @@ func init() {
 
 	opts = mhopts.SetupFlags(flag.CommandLine)
 
-	checkStr := "check checksum matches"
+	checkStr := "check checksum matches (a multihash, or a CID)"
 	flag.StringVar(&checkRaw, "check", "", checkStr)
 	flag.StringVar(&checkRaw, "c", "", checkStr+" (shorthand)")
 
+	inspectStr := "print the parts of a multihash (or a CID), and any inlined data, instead of hashing input"
+	flag.StringVar(&inspectRaw, "inspect", "", inspectStr)
+	flag.StringVar(&inspectRaw, "i", "", inspectStr+" (shorthand)")
+
 	helpStr := "display help message"
 	flag.BoolVar(&help, "help", false, helpStr)
 	flag.BoolVar(&help, "h", false, helpStr+" (shorthand)")
@@ -55,7 +60,7 @@ func parseFlags(o *mhopts.Options) error {
 
 	if checkRaw != "" {
 		var err error
-		checkMh, err = mhopts.Decode(o.Encoding, checkRaw)
+		checkMh, err = decodeArg(o, checkRaw)
 		if err != nil {
 			return fmt.Errorf("fail to decode check '%s': %s", checkRaw, err)
 		}
@@ -64,6 +69,53 @@ func parseFlags(o *mhopts.Options) error {
 	return nil
 }
 
+// decodeArg decodes a multihash given on the command line in the selected
+// encoding, or as a CID.
+func decodeArg(o *mhopts.Options, s string) (mh.Multihash, error) {
+	m, err := mhopts.Decode(o.Encoding, s)
+	if err == nil {
+		_, err = mh.Cast(m)
+	}
+	if err != nil {
+		// Users often paste a CID instead, so accept those too.
+		if cm, _, _, cidErr := mh.FromCID(s); cidErr == nil {
+			return cm, nil
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+// inspect prints the parts of a multihash. In quiet mode only the data
+// inlined in an identity multihash is printed, unquoted.
+func inspect(o *mhopts.Options, s string) error {
+	m, err := decodeArg(o, s)
+	if err != nil {
+		return fmt.Errorf("fail to decode '%s': %s", s, err)
+	}
+	dm, err := mh.Decode(m)
+	if err != nil {
+		return err
+	}
+
+	data, inline := m.InlineData()
+	if quiet {
+		if !inline {
+			return fmt.Errorf("%s multihash has no inlined data", dm.Name)
+		}
+		os.Stdout.Write(data)
+		return nil
+	}
+
+	fmt.Printf("code:   %s (0x%x)\n", dm.Name, dm.Code)
+	fmt.Printf("length: %d\n", dm.Length)
+	fmt.Printf("digest: %x\n", dm.Digest)
+	if inline {
+		fmt.Printf("inline: %q\n", data)
+	}
+	return nil
+}
+
 func getInput() (io.ReadCloser, error) {
 	args := flag.Args()
 
@@ -114,6 +166,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	if inspectRaw != "" {
+		checkErr(inspect(opts, inspectRaw))
+		return
+	}
+
 	inp, err := getInput()
 	checkErr(err)
 
@@ -17,6 +17,7 @@ var tCodes = map[uint64]string{
 	0x00:   "identity",
 	0x11:   "sha1",
 	0x12:   "sha2-256",
+	0x20:   "sha2-384",
 	0x13:   "sha2-512",
 	0x14:   "sha3-512",
 	0x15:   "sha3-384",
@@ -24,15 +25,28 @@ var tCodes = map[uint64]string{
 	0x17:   "sha3-224",
 	0x56:   "dbl-sha2-256",
 	0x22:   "murmur3-x64-64",
+	0x23:   "murmur3-32",
+	0x1022: "murmur3-x64-128",
 	0x1A:   "keccak-224",
 	0x1B:   "keccak-256",
 	0x1C:   "keccak-384",
 	0x1D:   "keccak-512",
 	0x1E:   "blake3",
+	0x1d01: "kangarootwelve",
 	0x18:   "shake-128",
 	0x19:   "shake-256",
 	0x1100: "x11",
+	0xd4:   "md4",
 	0xd5:   "md5",
+	0x1052: "ripemd-128",
+	0x1053: "ripemd-160",
+	0x1054: "ripemd-256",
+	0x1055: "ripemd-320",
+	0x534d: "sm3-256",
+	0xb3e1: "xxh-32",
+	0xb3e2: "xxh-64",
+	0xb3e3: "xxh3-64",
+	0xb3e4: "xxh3-128",
 	0x1012: "sha2-256-trunc254-padded",
 	0xb401: "poseidon-bls12_381-a2-fc1",
 }
@@ -50,6 +64,7 @@ var testCases = []TestCase{
 	{"0beec7b5", 0x11, "sha1"},
 	{"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", 0x12, "sha2-256"},
 	{"2c26b46b", 0x12, "sha2-256"},
+	{"98c11ffdfdd540676b1a137cb1a22b2a70350c9a44171d6b1180c6be5cbb2ee3f79d532c8a1dd9ef2e8e08e752a3babb", 0x20, "sha2-384"},
 	{"2c26b46b68ffc68ff99b453c1d30413413", 0xb240, "blake2b-512"},
 	{"243ddb9e", 0x22, "murmur3-x64-64"},
 	{"f00ba4", 0x1b, "keccak-256"},
@@ -189,7 +204,7 @@ func TestTable(t *testing.T) {
 
 	for name, code := range Names {
 		if tCodes[code] != name {
-			if strings.HasPrefix(name, "blake") {
+			if strings.HasPrefix(name, "blake") || strings.HasPrefix(name, "skein") {
 				// skip these
 				continue
 			}
@@ -203,7 +218,7 @@ func TestTable(t *testing.T) {
 
 	for code, name := range Codes {
 		if tCodes[code] != name {
-			if strings.HasPrefix(name, "blake") {
+			if strings.HasPrefix(name, "blake") || strings.HasPrefix(name, "skein") {
 				// skip these
 				continue
 			}
@@ -216,6 +231,21 @@ func TestTable(t *testing.T) {
 	}
 }
 
+// hasherExemptions lists the names which may lack a registered hasher, and
+// why.
+var hasherExemptions = map[string]string{}
+
+func TestNamesHaveHashers(t *testing.T) {
+	for name, code := range Names {
+		if _, ok := hasherExemptions[name]; ok {
+			continue
+		}
+		if _, err := GetHasher(code); err != nil {
+			t.Errorf("%s (0x%x) has no registered hasher: %s", name, code, err)
+		}
+	}
+}
+
 func ExampleDecode() {
 	// ignores errors for simplicity - don't do that at home.
 	buf, _ := hex.DecodeString("0beec7b5ea3f0fdbc95d0dd47f3c5bc275da8a33")
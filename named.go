@@ -0,0 +1,167 @@
+package multihash
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	mhreg "github.com/multiformats/go-multihash/core"
+)
+
+// ErrInvalidNamed is returned when a string is not a valid "algo:digest" form.
+var ErrInvalidNamed = errors.New("invalid named multihash")
+
+// NamedStyle selects how the digest is written by Multihash.FormatNamed.
+type NamedStyle int
+
+const (
+	// NamedHex writes the digest as lowercase hex, e.g. "sha2-256:2c26b4…".
+	NamedHex NamedStyle = iota
+	// NamedBase64 writes the digest as padded standard base64.
+	NamedBase64
+	// NamedBase64URL writes the digest as unpadded URL-safe base64.
+	NamedBase64URL
+)
+
+// FormatNamed returns the human-readable "name:digest" form of a multihash,
+// such as "sha2-256:2c26b46b…". The name is taken from Codes; codes without
+// a name are written as "0x<code>".
+//
+// When the digest is shorter than the default length of the hash function
+// (or the function is not registered), the length in bytes is appended to
+// the name, as in "sha2-256/20:…", so that ParseNamed can restore it.
+func (m Multihash) FormatNamed(style NamedStyle) (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+
+	var digest string
+	switch style {
+	case NamedHex:
+		digest = hex.EncodeToString(dm.Digest)
+	case NamedBase64:
+		digest = base64.StdEncoding.EncodeToString(dm.Digest)
+	case NamedBase64URL:
+		digest = base64.RawURLEncoding.EncodeToString(dm.Digest)
+	default:
+		return "", fmt.Errorf("unknown named style %d", style)
+	}
+
+	name := dm.Name
+	if name == "" {
+		name = "0x" + strconv.FormatUint(dm.Code, 16)
+	}
+	if l, ok := DefaultLengths[dm.Code]; !ok || l != dm.Length {
+		name += "/" + strconv.Itoa(dm.Length)
+	}
+	return name + ":" + digest, nil
+}
+
+// ParseNamed parses the "name:digest" form produced by FormatNamed.
+//
+// The name is looked up in Names, or may be a "0x"-prefixed hex code. An
+// optional "/length" suffix gives the digest length in bytes, which must be
+// one the registered hash function can produce (for most functions, at most
+// their default length); without it the default length is expected, except
+// for identity digests, which may have any length. The digest may be hex or
+// base64 (standard or URL-safe, padded or not).
+func ParseNamed(s string) (Multihash, error) {
+	name, digest, ok := strings.Cut(s, ":")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNamed, s)
+	}
+
+	length := -1
+	if n, l, ok := strings.Cut(name, "/"); ok {
+		v, err := strconv.Atoi(l)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("%w: bad length %q", ErrInvalidNamed, l)
+		}
+		name, length = n, v
+	}
+
+	code, ok := Names[name]
+	if !ok {
+		if !strings.HasPrefix(name, "0x") {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCode, name)
+		}
+		v, err := strconv.ParseUint(name[2:], 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCode, name)
+		}
+		code = v
+	}
+	// Identity digests may have any length.
+	if l, ok := DefaultLengths[code]; ok && code != IDENTITY {
+		if length < 0 {
+			length = l
+		} else if h, err := mhreg.GetVariableHasher(code, length); err != nil || h.Size() < length {
+			// Only lengths the registered hasher can produce are valid; for
+			// fixed-size hash functions, that is at most the default length.
+			return nil, fmt.Errorf("%w: %s digests cannot be %d bytes", ErrInvalidNamed, name, length)
+		}
+	}
+
+	buf, err := decodeNamedDigest(digest, length)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(buf, code)
+}
+
+// decodeNamedDigest decodes a hex or base64 digest. Hex is preferred when
+// both decode, unless the length is known and only base64 matches it.
+func decodeNamedDigest(s string, length int) ([]byte, error) {
+	if b, err := hex.DecodeString(s); err == nil && (length < 0 || len(b) == length) {
+		return b, nil
+	}
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		b, err := enc.DecodeString(s)
+		if err != nil {
+			continue
+		}
+		if length >= 0 && len(b) != length {
+			return nil, fmt.Errorf("%w: digest is %d bytes, expected %d", ErrInvalidNamed, len(b), length)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("%w: digest %q is neither hex nor base64", ErrInvalidNamed, s)
+}
+
+// Format implements fmt.Formatter. The %v verb prints the named hex form
+// (see FormatNamed), %x and %X print the raw multihash bytes as hex, and %s
+// and %q print String().
+func (m Multihash) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('#') {
+			fmt.Fprintf(f, "multihash.Multihash(%q)", m.HexString())
+			return
+		}
+		s, err := m.FormatNamed(NamedHex)
+		if err != nil {
+			s = m.HexString()
+		}
+		io.WriteString(f, s)
+	case 'x':
+		io.WriteString(f, hex.EncodeToString(m))
+	case 'X':
+		io.WriteString(f, strings.ToUpper(hex.EncodeToString(m)))
+	case 's':
+		io.WriteString(f, m.String())
+	case 'q':
+		io.WriteString(f, strconv.Quote(m.String()))
+	default:
+		fmt.Fprintf(f, "%%!%c(multihash.Multihash=%s)", verb, m.HexString())
+	}
+}
@@ -0,0 +1,143 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNamedRoundTrip(t *testing.T) {
+	for _, tc := range testCases {
+		m, err := tc.Multihash()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, style := range []NamedStyle{NamedHex, NamedBase64, NamedBase64URL} {
+			s, err := m.FormatNamed(style)
+			if err != nil {
+				t.Errorf("%s: %s", tc.name, err)
+				continue
+			}
+			m2, err := ParseNamed(s)
+			if err != nil {
+				t.Errorf("%s: failed to parse %q: %s", tc.name, s, err)
+				continue
+			}
+			if !bytes.Equal(m, m2) {
+				t.Errorf("%s: round trip of %q mismatch: %x != %x", tc.name, s, m, m2)
+			}
+		}
+	}
+}
+
+func TestFormatNamed(t *testing.T) {
+	full, err := Sum([]byte("foo"), SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trunc, err := Sum([]byte("foo"), SHA2_256, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknown, err := Encode([]byte{1, 2, 3}, 0x300000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		m        Multihash
+		style    NamedStyle
+		expected string
+	}{
+		{full, NamedHex, "sha2-256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+		{full, NamedBase64, "sha2-256:LCa0a2j/xo/5m0U8HTBBNBNCLXBkg7+g+YpeiGJm564="},
+		{full, NamedBase64URL, "sha2-256:LCa0a2j_xo_5m0U8HTBBNBNCLXBkg7-g-YpeiGJm564"},
+		{trunc, NamedHex, "sha2-256/20:2c26b46b68ffc68ff99b453c1d30413413422d70"},
+		{unknown, NamedHex, "0x300000/3:010203"},
+	} {
+		s, err := tc.m.FormatNamed(tc.style)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if s != tc.expected {
+			t.Errorf("expected %q, got %q", tc.expected, s)
+		}
+	}
+}
+
+func TestParseNamedIdentity(t *testing.T) {
+	foo, err := Encode([]byte("foo"), IDENTITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := Encode(nil, IDENTITY)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a length, an identity digest may have any length.
+	for _, tc := range []struct {
+		s        string
+		expected Multihash
+	}{
+		{"identity:666f6f", foo},
+		{"identity:Zm9v", foo},
+		{"identity/3:666f6f", foo},
+		{"identity:", empty},
+	} {
+		m, err := ParseNamed(tc.s)
+		if err != nil {
+			t.Errorf("%q: %s", tc.s, err)
+			continue
+		}
+		if !bytes.Equal(m, tc.expected) {
+			t.Errorf("%q: expected %x, got %x", tc.s, tc.expected, m)
+		}
+	}
+}
+
+func TestParseNamedErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"sha2-256",
+		"sha2-256:",
+		":2c26b46b",
+		"nope-256:2c26b46b",
+		"sha2-256:2c26b46b",
+		"sha2-256/x:2c26b46b",
+		"sha2-256/4:not base64 or hex",
+		"sha2-256/40:" + strings.Repeat("2c", 40),
+		"identity/2:666f6f",
+	} {
+		if _, err := ParseNamed(s); err == nil {
+			t.Errorf("expected %q to fail", s)
+		}
+	}
+
+	if _, err := ParseNamed("nope:00"); !errors.Is(err, ErrUnknownCode) {
+		t.Errorf("expected ErrUnknownCode, got %v", err)
+	}
+}
+
+func TestMultihashFormatter(t *testing.T) {
+	m, err := Sum([]byte("foo"), SHA1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for verb, expected := range map[string]string{
+		"%v": "sha1/4:0beec7b5",
+		"%x": "11040beec7b5",
+		"%X": "11040BEEC7B5",
+		"%s": "11040beec7b5",
+		"%q": `"11040beec7b5"`,
+	} {
+		if s := fmt.Sprintf(verb, m); s != expected {
+			t.Errorf("%s: expected %q, got %q", verb, expected, s)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package multihash
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidNI is returned when a string is not a valid RFC 6920 named
+// information URI, or a multihash cannot be written as one.
+var ErrInvalidNI = errors.New("invalid named information URI")
+
+// niAlgorithm is an entry of the IANA Named Information Hash Algorithm
+// Registry. Truncated suites map onto truncated multihashes of the full
+// hash function, the same way Sum truncates digests.
+type niAlgorithm struct {
+	id     int
+	name   string
+	code   uint64
+	length int
+}
+
+var niAlgorithms = []niAlgorithm{
+	{1, "sha-256", SHA2_256, 32},
+	{2, "sha-256-128", SHA2_256, 16},
+	{3, "sha-256-120", SHA2_256, 15},
+	{4, "sha-256-96", SHA2_256, 12},
+	{5, "sha-256-64", SHA2_256, 8},
+	{6, "sha-256-32", SHA2_256, 4},
+	{7, "sha-384", SHA2_384, 48},
+	{8, "sha-512", SHA2_512, 64},
+	{9, "sha3-224", SHA3_224, 28},
+	{10, "sha3-256", SHA3_256, 32},
+	{11, "sha3-384", SHA3_384, 48},
+	{12, "sha3-512", SHA3_512, 64},
+}
+
+// niLookup finds an algorithm by name, or by suite ID if byID is set.
+func niLookup(s string, byID bool) (niAlgorithm, bool) {
+	id := -1
+	if byID {
+		if v, err := strconv.Atoi(s); err == nil {
+			id = v
+		}
+	}
+	for _, a := range niAlgorithms {
+		if a.id == id || strings.EqualFold(a.name, s) {
+			return a, true
+		}
+	}
+	return niAlgorithm{}, false
+}
+
+func niFor(m Multihash) (niAlgorithm, DecodedMultihash, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return niAlgorithm{}, dm, err
+	}
+	for _, a := range niAlgorithms {
+		if a.code == dm.Code && a.length == dm.Length {
+			return a, dm, nil
+		}
+	}
+	return niAlgorithm{}, dm, fmt.Errorf("%w: no named information algorithm for %s truncated to %d bytes", ErrInvalidNI, dm.Name, dm.Length)
+}
+
+// FromNI parses an RFC 6920 named information URI into a multihash. Both the
+// "ni:" form, e.g. "ni:///sha-256;f4OxZX_x…", and the human-speakable "nih:"
+// form, e.g. "nih:sha-256-120;5326-9057-…;f", are accepted. The check digit
+// of a "nih:" URI is verified when present.
+func FromNI(uri string) (Multihash, error) {
+	scheme, rest, ok := strings.Cut(uri, ":")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNI, uri)
+	}
+	switch strings.ToLower(scheme) {
+	case "ni":
+		return fromNI(rest)
+	case "nih":
+		return fromNIH(rest)
+	default:
+		return nil, fmt.Errorf("%w: unknown scheme %q", ErrInvalidNI, scheme)
+	}
+}
+
+func fromNI(s string) (Multihash, error) {
+	s, _, _ = strings.Cut(s, "?")
+	s, ok := strings.CutPrefix(s, "//")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing authority", ErrInvalidNI)
+	}
+	_, path, ok := strings.Cut(s, "/")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidNI)
+	}
+	name, value, ok := strings.Cut(path, ";")
+	if !ok {
+		return nil, fmt.Errorf("%w: missing ';' in %q", ErrInvalidNI, path)
+	}
+
+	alg, ok := niLookup(name, false)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hash algorithm %q", ErrInvalidNI, name)
+	}
+	digest, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNI, err)
+	}
+	return encodeNI(alg, digest)
+}
+
+func fromNIH(s string) (Multihash, error) {
+	parts := strings.Split(s, ";")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidNI, s)
+	}
+
+	alg, ok := niLookup(parts[0], true)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown hash algorithm %q", ErrInvalidNI, parts[0])
+	}
+	value := strings.ToLower(strings.ReplaceAll(parts[1], "-", ""))
+	digest, err := hex.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNI, err)
+	}
+	if len(parts) == 3 {
+		if check := luhn16(value); !strings.EqualFold(parts[2], string(check)) {
+			return nil, fmt.Errorf("%w: check digit %q, expected %q", ErrInvalidNI, parts[2], string(check))
+		}
+	}
+	return encodeNI(alg, digest)
+}
+
+func encodeNI(alg niAlgorithm, digest []byte) (Multihash, error) {
+	if len(digest) != alg.length {
+		return nil, fmt.Errorf("%w: %s value is %d bytes, expected %d", ErrInvalidNI, alg.name, len(digest), alg.length)
+	}
+	return Encode(digest, alg.code)
+}
+
+// NIURI returns the RFC 6920 "ni:" URI of the multihash, with the given
+// (possibly empty) authority, e.g. "ni:///sha-256;f4OxZX_x…".
+//
+// Truncated sha2-256 multihashes are written using the matching truncated
+// suite, such as sha-256-128; other lengths cannot be represented.
+func (m Multihash) NIURI(authority string) (string, error) {
+	alg, dm, err := niFor(m)
+	if err != nil {
+		return "", err
+	}
+	return "ni://" + authority + "/" + alg.name + ";" + base64.RawURLEncoding.EncodeToString(dm.Digest), nil
+}
+
+// NIHURI returns the RFC 6920 human-speakable "nih:" URI of the multihash,
+// including the check digit, e.g. "nih:sha-256-120;53269057…;f".
+func (m Multihash) NIHURI() (string, error) {
+	alg, dm, err := niFor(m)
+	if err != nil {
+		return "", err
+	}
+	value := hex.EncodeToString(dm.Digest)
+	return "nih:" + alg.name + ";" + value + ";" + string(luhn16(value)), nil
+}
+
+// luhn16 computes the Luhn mod N check digit of a lowercase hex string, with
+// N = 16, as specified for "nih:" URIs.
+func luhn16(s string) byte {
+	const digits = "0123456789abcdef"
+	factor, sum := 2, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(digits, s[i])
+		factor = 3 - factor
+		sum += addend/16 + addend%16
+	}
+	return digits[(16-sum%16)%16]
+}
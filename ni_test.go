@@ -0,0 +1,122 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Examples from RFC 6920.
+func TestFromNI(t *testing.T) {
+	helloWorld := []byte("Hello World!")
+
+	for _, tc := range []struct {
+		uri    string
+		code   uint64
+		length int
+	}{
+		{"ni:///sha-256;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk", SHA2_256, -1},
+		{"ni://example.com/sha-256;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk?ct=text/plain", SHA2_256, -1},
+		{"NI:///SHA-256;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk", SHA2_256, -1},
+		{"ni:///sha-256-128;f4OxZX_x_FO5LcGBSKHWXQ", SHA2_256, 16},
+		{"nih:sha-256-32;7f83b165", SHA2_256, 4},
+		{"nih:6;7f83-b165;f", SHA2_256, 4},
+	} {
+		m, err := FromNI(tc.uri)
+		if err != nil {
+			t.Errorf("%q: %s", tc.uri, err)
+			continue
+		}
+		expected, err := Sum(helloWorld, tc.code, tc.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(m, expected) {
+			t.Errorf("%q: expected %s, got %s", tc.uri, expected.HexString(), m.HexString())
+		}
+	}
+}
+
+func TestNIH(t *testing.T) {
+	for _, uri := range []string{
+		"nih:sha-256-120;5326-9057-e12f-e2b7-4ba0-7c89-2560-a2;f",
+		"nih:3;532690-57e12f-e2b74b-a07c89-2560a2;f",
+	} {
+		m, err := FromNI(uri)
+		if err != nil {
+			t.Fatalf("%q: %s", uri, err)
+		}
+		s, err := m.NIHURI()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != "nih:sha-256-120;53269057e12fe2b74ba07c892560a2;f" {
+			t.Errorf("unexpected nih URI %q", s)
+		}
+	}
+
+	if _, err := FromNI("nih:sha-256-120;5326-9057-e12f-e2b7-4ba0-7c89-2560-a2;e"); !errors.Is(err, ErrInvalidNI) {
+		t.Errorf("expected a bad check digit to fail, got %v", err)
+	}
+}
+
+func TestNIURIRoundTrip(t *testing.T) {
+	for _, length := range []int{-1, 16, 15, 12, 8, 4} {
+		m, err := Sum([]byte("foo"), SHA2_256, length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, authority := range []string{"", "example.com"} {
+			uri, err := m.NIURI(authority)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m2, err := FromNI(uri)
+			if err != nil {
+				t.Fatalf("%q: %s", uri, err)
+			}
+			if !bytes.Equal(m, m2) {
+				t.Errorf("%q: round trip mismatch", uri)
+			}
+		}
+
+		uri, err := m.NIHURI()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m2, err := FromNI(uri)
+		if err != nil {
+			t.Fatalf("%q: %s", uri, err)
+		}
+		if !bytes.Equal(m, m2) {
+			t.Errorf("%q: round trip mismatch", uri)
+		}
+	}
+}
+
+func TestNIErrors(t *testing.T) {
+	trunc, err := Sum([]byte("foo"), SHA2_256, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trunc.NIURI(""); !errors.Is(err, ErrInvalidNI) {
+		t.Errorf("expected ErrInvalidNI for an unregistered truncation, got %v", err)
+	}
+
+	for _, uri := range []string{
+		"",
+		"http://example.com/sha-256;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk",
+		"ni:sha-256;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk",
+		"ni:///sha-256",
+		"ni:///md5;f4OxZX_x_FO5LcGBSKHWXfwtSx-j1ncoSt3SABJtkGk",
+		"ni:///sha-256;f4OxZX_x_FO5LcGBSKHWXQ",
+		"ni:///sha-256;f4OxZX_x/FO5LcGBSKHWXfwtSx+j1ncoSt3SABJtkGk",
+		"nih:sha-256-32",
+		"nih:sha-256-32;7f83b16",
+		"nih:99;7f83b165",
+	} {
+		if _, err := FromNI(uri); !errors.Is(err, ErrInvalidNI) {
+			t.Errorf("%q: expected ErrInvalidNI, got %v", uri, err)
+		}
+	}
+}
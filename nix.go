@@ -0,0 +1,208 @@
+package multihash
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidNixHash is returned when a string is not a valid Nix hash, or a
+// multihash cannot be written as one.
+var ErrInvalidNixHash = errors.New("invalid nix hash")
+
+// NixFormat selects how Multihash.NixHash writes a hash.
+type NixFormat int
+
+const (
+	// NixBase32 is the "sha256:<nix32>" form used by most Nix tooling.
+	NixBase32 NixFormat = iota
+	// NixBase32Bare is the nix32 digest alone, as in older nixpkgs
+	// "sha256 = …" attributes. The algorithm is implied by the length.
+	NixBase32Bare
+	// NixBase16 is the "sha256:<hex>" form.
+	NixBase16
+	// NixSRI is the SRI-style "sha256-<base64>" form used by newer nixpkgs.
+	NixSRI
+)
+
+// nixAlgorithms are the hash algorithms supported by Nix.
+var nixAlgorithms = []struct {
+	name string
+	code uint64
+	size int
+}{
+	{"md5", MD5, 16},
+	{"sha1", SHA1, 20},
+	{"sha256", SHA2_256, 32},
+	{"sha512", SHA2_512, 64},
+}
+
+// nix32Alphabet is the alphabet of Nix's base32 encoding, which omits the
+// letters e, o, u and t.
+const nix32Alphabet = "0123456789abcdfghijklmnpqrsvwxyz"
+
+// nix32Len returns the length of the nix32 encoding of size bytes.
+func nix32Len(size int) int {
+	return (size*8-1)/5 + 1
+}
+
+// encodeNix32 encodes bytes the way Nix does: five bits at a time starting
+// from the least significant bits of the first byte, emitted in reverse
+// order.
+func encodeNix32(b []byte) string {
+	out := make([]byte, nix32Len(len(b)))
+	for n := range out {
+		bit := n * 5
+		i, j := bit/8, uint(bit%8)
+		c := b[i] >> j
+		if i+1 < len(b) {
+			c |= b[i+1] << (8 - j)
+		}
+		out[len(out)-1-n] = nix32Alphabet[c&0x1f]
+	}
+	return string(out)
+}
+
+// decodeNix32 is the inverse of encodeNix32.
+func decodeNix32(s string, size int) ([]byte, error) {
+	if len(s) != nix32Len(size) {
+		return nil, fmt.Errorf("%w: nix32 string of %d bytes must be %d characters", ErrInvalidNixHash, size, nix32Len(size))
+	}
+	out := make([]byte, size)
+	for n := 0; n < len(s); n++ {
+		digit := strings.IndexByte(nix32Alphabet, s[len(s)-1-n])
+		if digit < 0 {
+			return nil, fmt.Errorf("%w: invalid nix32 character %q", ErrInvalidNixHash, s[len(s)-1-n])
+		}
+		bit := n * 5
+		i, j := bit/8, uint(bit%8)
+		out[i] |= byte(digit << j)
+		carry := byte(digit >> (8 - j))
+		if i+1 < size {
+			out[i+1] |= carry
+		} else if carry != 0 {
+			return nil, fmt.Errorf("%w: nix32 string has excess bits", ErrInvalidNixHash)
+		}
+	}
+	return out, nil
+}
+
+// FromNixHash parses a Nix hash into a multihash. It accepts:
+//
+//   - "<algo>:<digest>", where the digest is nix32, hex or base64, told apart
+//     by their lengths as Nix does;
+//   - SRI-style "<algo>-<base64>";
+//   - a bare nix32 digest, whose algorithm is inferred from its length.
+//
+// The supported algorithms are md5, sha1, sha256 and sha512.
+func FromNixHash(s string) (Multihash, error) {
+	if name, digest, ok := strings.Cut(s, ":"); ok {
+		alg := nixLookup(name)
+		if alg < 0 {
+			return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidNixHash, name)
+		}
+		return decodeNixDigest(digest, alg)
+	}
+
+	if name, digest, ok := strings.Cut(s, "-"); ok {
+		alg := nixLookup(name)
+		if alg < 0 {
+			return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidNixHash, name)
+		}
+		b, err := base64.StdEncoding.DecodeString(digest)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNixHash, err)
+		}
+		return encodeNixDigest(b, alg)
+	}
+
+	for i, a := range nixAlgorithms {
+		if len(s) == nix32Len(a.size) {
+			b, err := decodeNix32(s, a.size)
+			if err != nil {
+				return nil, err
+			}
+			return encodeNixDigest(b, i)
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidNixHash, s)
+}
+
+func nixLookup(name string) int {
+	for i, a := range nixAlgorithms {
+		if a.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func decodeNixDigest(s string, alg int) (Multihash, error) {
+	size := nixAlgorithms[alg].size
+	switch len(s) {
+	case hex.EncodedLen(size):
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNixHash, err)
+		}
+		return encodeNixDigest(b, alg)
+	case nix32Len(size):
+		b, err := decodeNix32(s, size)
+		if err != nil {
+			return nil, err
+		}
+		return encodeNixDigest(b, alg)
+	case base64.StdEncoding.EncodedLen(size):
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNixHash, err)
+		}
+		return encodeNixDigest(b, alg)
+	default:
+		return nil, fmt.Errorf("%w: digest %q has the wrong length for %s", ErrInvalidNixHash, s, nixAlgorithms[alg].name)
+	}
+}
+
+func encodeNixDigest(b []byte, alg int) (Multihash, error) {
+	a := nixAlgorithms[alg]
+	if len(b) != a.size {
+		return nil, fmt.Errorf("%w: %s digest is %d bytes, expected %d", ErrInvalidNixHash, a.name, len(b), a.size)
+	}
+	return Encode(b, a.code)
+}
+
+// NixHash returns the multihash as a Nix hash in the given format.
+//
+// Only untruncated md5, sha1, sha2-256 and sha2-512 multihashes can be
+// represented.
+func (m Multihash) NixHash(format NixFormat) (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range nixAlgorithms {
+		if a.code != dm.Code {
+			continue
+		}
+		if dm.Length != a.size {
+			return "", fmt.Errorf("%w: %s multihash is truncated to %d bytes", ErrInvalidNixHash, dm.Name, dm.Length)
+		}
+
+		switch format {
+		case NixBase32:
+			return a.name + ":" + encodeNix32(dm.Digest), nil
+		case NixBase32Bare:
+			return encodeNix32(dm.Digest), nil
+		case NixBase16:
+			return a.name + ":" + hex.EncodeToString(dm.Digest), nil
+		case NixSRI:
+			return a.name + "-" + base64.StdEncoding.EncodeToString(dm.Digest), nil
+		default:
+			return "", fmt.Errorf("unknown nix hash format %d", format)
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not supported by nix", ErrInvalidNixHash, dm.Name)
+}
@@ -0,0 +1,135 @@
+package multihash
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestNixFixtures checks round trips against fixed-output hashes taken from
+// nixpkgs.
+func TestNixFixtures(t *testing.T) {
+	file, err := os.Open("testdata/nix-hashes.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	values, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) < 2 || strings.Join(values[0], ",") != "name,nix32,sri,base16" {
+		t.Fatal("fixture format has changed")
+	}
+
+	for _, v := range values[1:] {
+		name, nix32, sri, base16 := v[0], v[1], v[2], v[3]
+		_, bare, _ := strings.Cut(nix32, ":")
+
+		expected, err := FromNixHash(base16)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+
+		for _, tc := range []struct {
+			s      string
+			format NixFormat
+		}{
+			{nix32, NixBase32},
+			{bare, NixBase32Bare},
+			{base16, NixBase16},
+			{sri, NixSRI},
+		} {
+			m, err := FromNixHash(tc.s)
+			if err != nil {
+				t.Errorf("%s: %q: %s", name, tc.s, err)
+				continue
+			}
+			if !bytes.Equal(m, expected) {
+				t.Errorf("%s: %q decoded to %s, expected %s", name, tc.s, m.HexString(), expected.HexString())
+			}
+
+			s, err := expected.NixHash(tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s != tc.s {
+				t.Errorf("%s: expected %q, got %q", name, tc.s, s)
+			}
+		}
+	}
+}
+
+func TestNixEmptyFile(t *testing.T) {
+	m, err := Sum(nil, SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := m.NixHash(NixBase32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "sha256:0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73" {
+		t.Errorf("unexpected nix hash %q", s)
+	}
+}
+
+func TestNixRoundTrip(t *testing.T) {
+	for _, code := range []uint64{MD5, SHA1, SHA2_256, SHA2_512} {
+		m, err := Sum([]byte("foo"), code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []NixFormat{NixBase32, NixBase32Bare, NixBase16, NixSRI} {
+			s, err := m.NixHash(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m2, err := FromNixHash(s)
+			if err != nil {
+				t.Fatalf("%q: %s", s, err)
+			}
+			if !bytes.Equal(m, m2) {
+				t.Errorf("%q: round trip mismatch", s)
+			}
+		}
+	}
+}
+
+func TestNixHashErrors(t *testing.T) {
+	trunc, err := Sum([]byte("foo"), SHA2_256, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trunc.NixHash(NixBase32); !errors.Is(err, ErrInvalidNixHash) {
+		t.Errorf("expected ErrInvalidNixHash for a truncated multihash, got %v", err)
+	}
+	sha3, err := Sum([]byte("foo"), SHA3_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sha3.NixHash(NixBase32); !errors.Is(err, ErrInvalidNixHash) {
+		t.Errorf("expected ErrInvalidNixHash for sha3-256, got %v", err)
+	}
+
+	for _, s := range []string{
+		"",
+		"sha256:",
+		"sha3:0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73",
+		// 'e' is not in the nix32 alphabet.
+		"sha256:emdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73",
+		// The leading character carries bits beyond the end of the digest.
+		"sha256:zmdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c73",
+		"sha256:0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c7",
+		"sha256-jZkUKv2SV28wsM18tCqNxoCZmLxdYH2Idh9RLibH2y==",
+		"0mdqa9w1p6cmli6976v4wi0sw9r4p5prkj7lzfd1877wk11c9c7",
+	} {
+		if _, err := FromNixHash(s); !errors.Is(err, ErrInvalidNixHash) {
+			t.Errorf("%q: expected ErrInvalidNixHash, got %v", s, err)
+		}
+	}
+}
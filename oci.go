@@ -0,0 +1,143 @@
+package multihash
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidOCIDigest is returned when a string does not follow the OCI
+// image-spec digest grammar, or its encoded part does not fit the algorithm.
+var ErrInvalidOCIDigest = errors.New("invalid OCI digest")
+
+// ErrOCIAlgorithm is returned when an algorithm has no OCI digest equivalent,
+// either because an OCI digest names an algorithm that is not supported here,
+// or because a multihash uses a hash function that OCI does not register.
+type ErrOCIAlgorithm struct {
+	Algorithm string
+}
+
+func (e ErrOCIAlgorithm) Error() string {
+	return fmt.Sprintf("no OCI digest algorithm for %q", e.Algorithm)
+}
+
+// ociAlgorithms maps the algorithms registered by the OCI image-spec to
+// their multihash codes.
+var ociAlgorithms = map[string]uint64{
+	"sha256": SHA2_256,
+	"sha512": SHA2_512,
+}
+
+// ociNames is the reverse of ociAlgorithms.
+var ociNames = map[uint64]string{
+	SHA2_256: "sha256",
+	SHA2_512: "sha512",
+}
+
+// FromOCIDigest parses an OCI / Docker content digest such as
+// "sha256:2c26b46b…" into a multihash.
+//
+// The string must follow the digest grammar of the OCI image-spec. For the
+// registered sha256 and sha512 algorithms the encoded part must be lowercase
+// hex of the full digest length. Other well-formed algorithms are rejected
+// with ErrOCIAlgorithm.
+func FromOCIDigest(s string) (Multihash, error) {
+	alg, encoded, ok := strings.Cut(s, ":")
+	if !ok || !validOCIAlgorithm(alg) || !validOCIEncoded(encoded) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOCIDigest, s)
+	}
+
+	code, ok := ociAlgorithms[alg]
+	if !ok {
+		return nil, ErrOCIAlgorithm{alg}
+	}
+
+	size := DefaultLengths[code]
+	if len(encoded) != size*2 || strings.ToLower(encoded) != encoded {
+		return nil, fmt.Errorf("%w: %s digest must be %d lowercase hex characters", ErrInvalidOCIDigest, alg, size*2)
+	}
+	digest, err := hex.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidOCIDigest, err)
+	}
+
+	return Encode(digest, code)
+}
+
+// OCIDigest returns the OCI content digest form of the multihash, e.g.
+// "sha256:2c26b46b…".
+//
+// Only untruncated sha2-256 and sha2-512 multihashes can be represented.
+func (m Multihash) OCIDigest() (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+
+	alg, ok := ociNames[dm.Code]
+	if !ok {
+		name := dm.Name
+		if name == "" {
+			name = fmt.Sprintf("0x%x", dm.Code)
+		}
+		return "", ErrOCIAlgorithm{name}
+	}
+	if dm.Length != DefaultLengths[dm.Code] {
+		return "", fmt.Errorf("%w: %s multihash is truncated to %d bytes", ErrInvalidOCIDigest, dm.Name, dm.Length)
+	}
+
+	return alg + ":" + hex.EncodeToString(dm.Digest), nil
+}
+
+// VerifyOCIBlob reads all of r and checks that it matches the OCI digest.
+// A mismatch is reported as an ErrMismatch, which matches ErrDigestMismatch.
+func VerifyOCIBlob(r io.Reader, digest string) error {
+	m, err := FromOCIDigest(digest)
+	if err != nil {
+		return err
+	}
+	return m.VerifyStream(r)
+}
+
+// validOCIAlgorithm checks the grammar:
+//
+//	algorithm           ::= algorithm-component (algorithm-separator algorithm-component)*
+//	algorithm-component ::= [a-z0-9]+
+//	algorithm-separator ::= [+._-]
+func validOCIAlgorithm(s string) bool {
+	component := false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+			component = true
+		case c == '+' || c == '.' || c == '_' || c == '-':
+			if !component {
+				return false
+			}
+			component = false
+		default:
+			return false
+		}
+	}
+	return component
+}
+
+// validOCIEncoded checks the grammar:
+//
+//	encoded ::= [a-zA-Z0-9=_-]+
+func validOCIEncoded(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '=' || c == '_' || c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
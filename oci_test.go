@@ -0,0 +1,94 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestOCIDigestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		code   uint64
+		digest string
+	}{
+		{SHA2_256, "sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+		{SHA2_512, "sha512:f7fbba6e0636f890e56fbbf3283e524c6fa3204ae298382d624741d0dc6638326e282c41be5e4254d8820772c5518a2c5a8c0c7f7eda19594a7eb539453e1ed7"},
+	} {
+		expected, err := Sum([]byte("foo"), tc.code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := FromOCIDigest(tc.digest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(m, expected) {
+			t.Errorf("FromOCIDigest(%q) = %x, expected %x", tc.digest, m, expected)
+		}
+
+		s, err := m.OCIDigest()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tc.digest {
+			t.Errorf("expected %q, got %q", tc.digest, s)
+		}
+
+		if err := VerifyOCIBlob(strings.NewReader("foo"), tc.digest); err != nil {
+			t.Error(err)
+		}
+		if err := VerifyOCIBlob(strings.NewReader("bar"), tc.digest); !errors.Is(err, ErrDigestMismatch) {
+			t.Errorf("expected ErrDigestMismatch, got %v", err)
+		}
+	}
+}
+
+func TestFromOCIDigestErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"sha256",
+		"sha256:",
+		":2c26b46b",
+		"SHA256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		"sha256:2C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE",
+		"sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7",
+		"sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae00",
+		"sha256:2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7zz",
+		"sha256+:2c26b46b",
+		"sha256:2c26/46b",
+	} {
+		if _, err := FromOCIDigest(s); !errors.Is(err, ErrInvalidOCIDigest) {
+			t.Errorf("%q: expected ErrInvalidOCIDigest, got %v", s, err)
+		}
+	}
+
+	var algErr ErrOCIAlgorithm
+	if _, err := FromOCIDigest("multihash+base58:QmRZxt2b1FVZPNqd8hsiykDL3TdBDeTSPX9Kv46HmX4Gx8"); !errors.As(err, &algErr) {
+		t.Errorf("expected ErrOCIAlgorithm, got %v", err)
+	} else if algErr.Algorithm != "multihash+base58" {
+		t.Errorf("unexpected algorithm %q", algErr.Algorithm)
+	}
+}
+
+func TestOCIDigestErrors(t *testing.T) {
+	trunc, err := Sum([]byte("foo"), SHA2_256, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trunc.OCIDigest(); !errors.Is(err, ErrInvalidOCIDigest) {
+		t.Errorf("expected ErrInvalidOCIDigest for a truncated multihash, got %v", err)
+	}
+
+	sha1, err := Sum([]byte("foo"), SHA1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var algErr ErrOCIAlgorithm
+	if _, err := sha1.OCIDigest(); !errors.As(err, &algErr) {
+		t.Errorf("expected ErrOCIAlgorithm, got %v", err)
+	} else if algErr.Algorithm != "sha1" {
+		t.Errorf("unexpected algorithm %q", algErr.Algorithm)
+	}
+}
@@ -19,6 +19,15 @@ package all
 import (
 	_ "github.com/multiformats/go-multihash/register/blake2"
 	_ "github.com/multiformats/go-multihash/register/blake3"
+	_ "github.com/multiformats/go-multihash/register/filecoin"
+	_ "github.com/multiformats/go-multihash/register/k12"
+	_ "github.com/multiformats/go-multihash/register/md4"
 	_ "github.com/multiformats/go-multihash/register/murmur3"
+	_ "github.com/multiformats/go-multihash/register/poseidon"
+	_ "github.com/multiformats/go-multihash/register/ripemd"
 	_ "github.com/multiformats/go-multihash/register/sha3"
+	_ "github.com/multiformats/go-multihash/register/skein"
+	_ "github.com/multiformats/go-multihash/register/sm3"
+	_ "github.com/multiformats/go-multihash/register/x11"
+	_ "github.com/multiformats/go-multihash/register/xxhash"
 )
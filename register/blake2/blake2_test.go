@@ -115,3 +115,110 @@ func TestBlake2(t *testing.T) {
 		})
 	}
 }
+
+func TestBlake2s(t *testing.T) {
+	data := mustHexDecode("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f5051525354555657")
+
+	for _, tc := range [...]struct {
+		expectedResultData   []byte
+		expectedResultNoData []byte
+	}{
+		{mustHexDecode("06"), mustHexDecode("a1")},
+		{mustHexDecode("be76"), mustHexDecode("8f38")},
+		{mustHexDecode("c8b33e"), mustHexDecode("ad25c7")},
+		{mustHexDecode("5e4c1206"), mustHexDecode("36e9d246")},
+		{mustHexDecode("915b401b5b"), mustHexDecode("0c58705f4f")},
+		{mustHexDecode("cc2cb68e62cc"), mustHexDecode("ef6e31b489af")},
+		{mustHexDecode("263ae94320bd38"), mustHexDecode("de1931c0881609")},
+		{mustHexDecode("c33b9b986d885679"), mustHexDecode("ef2a8b78dd80da9c")},
+		{mustHexDecode("b61e0be3a8b3559dc6"), mustHexDecode("ab1d1d2e423e803924")},
+		{mustHexDecode("434b6e0f928c553a3565"), mustHexDecode("1bf21a98c78a1c376ae9")},
+		{mustHexDecode("fdc1c08d0fca043a75351a"), mustHexDecode("567004bf96e4a25773ebf4")},
+		{mustHexDecode("9a2fe307ef4af0ba4e3ccb22"), mustHexDecode("a10486e873ac3dcef45bbba2")},
+		{mustHexDecode("fd06be71d8d7af14aec627e89a"), mustHexDecode("758fe2c70fa22afd145e08c8c1")},
+		{mustHexDecode("b0043aaa820ae5feab97f5e97105"), mustHexDecode("80b1192a5cd72ee97b703b91616b")},
+		{mustHexDecode("28d34599f61adebfe51e23c62bce7f"), mustHexDecode("89d14662cd5728a3b60c6240c99a62")},
+		{mustHexDecode("3740022a6174bde156c7880003364907"), mustHexDecode("64550d6ffe2c0a01a14aba1eade0200c")},
+		{mustHexDecode("def5e60c9e0e5329f97d3af8eed13a2ab5"), mustHexDecode("20ec13a2883f05fd6507dbfd3874db7cba")},
+		{mustHexDecode("b4b050e6842a3259f6e0753a6926735c3157"), mustHexDecode("81ac86b9e6a116711f1424875720a2121378")},
+		{mustHexDecode("58d262fe687d3d9d35b7afdf79470e97fa9f51"), mustHexDecode("d2e2d7deca16f2f6b2960de81ac5547fad2c7f")},
+		{mustHexDecode("f94971cff90543b01dd32c5589032b0c05ac179f"), mustHexDecode("354c9c33f735962418bdacb9479873429c34916f")},
+		{mustHexDecode("7419e54b66aff12c19ddca83dfe57e96793ba1095d"), mustHexDecode("dc2ea42df9976a83adfbf854f30051b68e7115a753")},
+		{mustHexDecode("178db99d9cd09a33ae587525353e2557056fd6fdbb5e"), mustHexDecode("c3296897bb43ce9211a9121cf1767b34b8d9349fb06b")},
+		{mustHexDecode("c039cf58b039081fa68c8262b7d0a28f6ae36a1bae8564"), mustHexDecode("c3aa6d758b7019fdea61f4fad3a4338bf378b2d32bff7c")},
+		{mustHexDecode("23e0460a6083aa32a0d08270f1712d240fda212d65f6cc1e"), mustHexDecode("a847d26c2f966c5c4cc222b174918a56037cdee34b3f872f")},
+		{mustHexDecode("d193f3ae9dd58e7f7dfe2ba83fbd0195ea50ce40f3f4f12f22"), mustHexDecode("8ac6416804b719c221366263abdda861ec19ebb2f8f75dd4ea")},
+		{mustHexDecode("1f34f2ecb17de6388180244d8a056694a8ecbb69cbe88117797c"), mustHexDecode("e90af5ca5d02f36adec62d0588ab5dc21c81e91cde3e6cd36aad")},
+		{mustHexDecode("25b252c1a26f4309d88437523706e4e93c187e1927a1d323d320e9"), mustHexDecode("dae10354a41d7b16066966d565e4d063ef5a9de5ac4f9583ed4961")},
+		{mustHexDecode("f2761bb7e85bb2953fb99aff5f2ec50c6799b140359165035333c8f9"), mustHexDecode("1fa1291e65248b37b3433475b2a0dd63d54a11ecc4e3e034e7bc1ef4")},
+		{mustHexDecode("db74ee76947f04d14db12da866956b96048013208852e678b1ab7ee40d"), mustHexDecode("9965fe1085a8d2294d96b40a6494ea1b8367551d69ea07188b2598967e")},
+		{mustHexDecode("3dcd2cbec1c48a74fb8d5c1dd4d368094c7842c6d42d49da46eb7a37132f"), mustHexDecode("526f4a3a959bd5d1665241f94c619dbb367250c3bdcfeb3b29737b6ea44d")},
+		{mustHexDecode("3411d20440829cd4bfe227efd9a895aa5de4ded4c937648edf1d1258b46b2c"), mustHexDecode("1f57c56334f1ba2d62275430fdc2d2301017ba6be19864dac5a5eca012da4d")},
+		{mustHexDecode("0c75c1a15cf34a314ee478f4a5ce0b8a6b36528ef7a820696c3e4246c5a15864"), mustHexDecode("69217a3079908094e11121d042354a7c1f55b6482ca1a51e1b250dfd1ed0eef9")},
+	} {
+		size := uint64(len(tc.expectedResultData))
+		t.Run(fmt.Sprintf("blake2s_%d", size*8), func(t *testing.T) {
+			h, err := multihash.GetHasher(blake2s_min + size - 1)
+			if err != nil {
+				t.Errorf("failed to get: %s", err.Error())
+				return
+			}
+
+			if result := h.Sum(nil); !bytes.Equal(result, tc.expectedResultNoData) {
+				t.Errorf("digest empty doesn't match, expected %s; got %s", hex.EncodeToString(tc.expectedResultNoData), hex.EncodeToString(result))
+			}
+			n, err := h.Write(data)
+			if err != nil {
+				t.Errorf("hashing data failed: %s", err.Error())
+				return
+			}
+			if n != len(data) {
+				t.Errorf("not enough bytes hashed, expected %d; got %d", len(data), n)
+				return
+			}
+			if result := h.Sum(nil); !bytes.Equal(result, tc.expectedResultData) {
+				t.Errorf("digest full doesn't match, expected %s; got %s", hex.EncodeToString(tc.expectedResultData), hex.EncodeToString(result))
+			}
+		})
+	}
+}
+
+func TestBlake2sKeyed(t *testing.T) {
+	key := mustHexDecode("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	data := mustHexDecode("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f5051525354555657")
+
+	for _, tc := range [...]struct {
+		keyLen   int
+		size     int
+		dataLen  int
+		expected string
+	}{
+		{32, 32, 0, "48a8997da407876b3d79c0d92325ad3b89cbb754d86ab71aee047ad345fd2c49"},
+		{32, 32, 88, "cfce55ebafc840d7ae48281c7fd57ec8b482d4b704437495495ac414cf4a374b"},
+		{16, 20, 88, "9e64f243647833e2b126ead9095029ab4f3f1815"},
+		{1, 8, 64, "a183a5f70489e729"},
+		{32, 32, 64, "8975b0577fd35566d750b362b0897a26c399136df07bababbde6203ff2954ed4"},
+		{32, 32, 65, "21fe0ceb0052be7fb0f004187cacd7de67fa6eb0938d927677f2398c132317a8"},
+	} {
+		h, err := NewBlake2s(tc.size, key[:tc.keyLen])
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write in two pieces to exercise the block buffering.
+		h.Write(data[:tc.dataLen/2])
+		h.Write(data[tc.dataLen/2 : tc.dataLen])
+		if result := hex.EncodeToString(h.Sum(nil)); result != tc.expected {
+			t.Errorf("key %d, size %d, data %d: expected %s; got %s", tc.keyLen, tc.size, tc.dataLen, tc.expected, result)
+		}
+	}
+
+	if _, err := NewBlake2s(0, nil); err == nil {
+		t.Error("expected an error for a zero digest size")
+	}
+	if _, err := NewBlake2s(Blake2sMaxSize+1, nil); err == nil {
+		t.Error("expected an error for an oversized digest")
+	}
+	if _, err := NewBlake2s(Blake2sMaxSize, make([]byte, Blake2sMaxKeySize+1)); err == nil {
+		t.Error("expected an error for an oversized key")
+	}
+}
@@ -8,7 +8,10 @@ It is meant to be used as a side-effecting import, e.g.
 	)
 
 This package registers several multihashes for the blake2 family
-(both the 's' and the 'b' variants, and in a variety of sizes).
+(both the 's' and the 'b' variants, in every size).
+
+It also provides NewBlake2s, for blake2s digest sizes and keys which
+golang.org/x/crypto/blake2s does not support.
 */
 package blake2
 
@@ -30,14 +33,30 @@ const (
 
 func init() {
 	// blake2s
-	// This package only enables support for 32byte (256 bit) blake2s.
-	multihash.Register(blake2s_min+31, func() hash.Hash {
-		h, err := blake2s.New256(nil)
-		if err != nil {
-			panic(err)
-		}
-		return h
-	})
+	// Each size is a distinct hash, with the digest length in its parameter
+	// block. x/crypto only provides blake2s-256, so the others use NewBlake2s.
+	for c := uint64(blake2s_min); c <= blake2s_max; c++ {
+		size := int(c - blake2s_min + 1)
+
+		multihash.RegisterVariableSize(c, func(sizeHint int) (hash.Hash, bool) {
+			if sizeHint > size {
+				return nil, false
+			}
+			if size == blake2s.Size {
+				hasher, err := blake2s.New256(nil)
+				if err != nil {
+					panic(err)
+				}
+				return hasher, true
+			}
+			hasher, err := NewBlake2s(size, nil)
+			if err != nil {
+				panic(err)
+			}
+			return hasher, true
+		})
+		multihash.RegisterStrength(c, multihash.Strength{Bits: size * 8 / 2, Cryptographic: true})
+	}
 
 	// blake2b
 	// There's a whole range of these.
@@ -51,5 +70,6 @@ func init() {
 			}
 			return hasher
 		})
+		multihash.RegisterStrength(c, multihash.Strength{Bits: size * 8 / 2, Cryptographic: true})
 	}
 }
@@ -30,4 +30,5 @@ func init() {
 		h := blake3.New(size, nil)
 		return h, true
 	})
+	multihash.RegisterStrength(multihash.BLAKE3, multihash.Strength{Bits: 128, Cryptographic: true})
 }
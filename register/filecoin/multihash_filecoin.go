@@ -0,0 +1,212 @@
+/*
+This package registers the Filecoin piece commitment (commP) calculator as
+the sha2-256-trunc254-padded multihash.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/filecoin"
+	)
+
+sha2-256-trunc254-padded is the node hash of Filecoin's proving trees:
+sha2-256 with the two most significant bits of the digest zeroed, so that
+every node fits in a BLS12-381 field element. In multihashes it identifies
+the root of such a tree, so the registered hasher computes the piece
+commitment of what is written to it rather than a plain truncated sha2-256.
+NodeHash is the node hash itself.
+
+A piece commitment does not encode the unpadded size of the data: data and
+the same data followed by zeros share a commitment.
+The code is therefore registered with no strength, like identity, and
+VerifyAny prefers any cryptographic hash over it. Callers must check the
+unpadded size separately, as deals do with their piece size.
+*/
+package filecoin
+
+import (
+	"crypto/sha256"
+	"hash"
+	"io"
+	"math/bits"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	multihash.Register(multihash.SHA2_256_TRUNC254_PADDED, func() hash.Hash { return new(Calc) })
+	multihash.RegisterStrength(multihash.SHA2_256_TRUNC254_PADDED, multihash.Strength{})
+}
+
+// NodeSize is the size of a node of the tree, in bytes.
+const NodeSize = 32
+
+// Fr32 padding spreads every 127 bytes of data over 128 bytes, as four
+// 254-bit field elements.
+const (
+	unpaddedChunk = 127
+	paddedChunk   = 128
+)
+
+// maxLevels bounds the height of a tree: a piece cannot hold more than
+// 2^64 bytes, so it has fewer than 2^59 leaves.
+const maxLevels = 64
+
+// zeroComms holds the root of a tree over all-zero leaves, by level.
+var zeroComms [maxLevels][NodeSize]byte
+
+func init() {
+	for i := 1; i < maxLevels; i++ {
+		zeroComms[i] = NodeHash(zeroComms[i-1], zeroComms[i-1])
+	}
+}
+
+// NodeHash returns the parent of two nodes: their sha2-256 with the two most
+// significant bits of the last byte cleared.
+func NodeHash(left, right [NodeSize]byte) [NodeSize]byte {
+	h := sha256.New()
+	h.Write(left[:])
+	h.Write(right[:])
+	var out [NodeSize]byte
+	h.Sum(out[:0])
+	out[NodeSize-1] &= 0x3f
+	return out
+}
+
+// fr32Pad pads one chunk of 127 bytes of data into out, inserting two zero
+// bits after every 254 bits. Bits are taken least significant first.
+func fr32Pad(out *[paddedChunk]byte, in *[unpaddedChunk]byte) {
+	for q := 0; q < 4; q++ {
+		start := q * 254
+		off, shift := start/8, uint(start%8)
+		for i := 0; i < NodeSize; i++ {
+			b := in[off+i] >> shift
+			if shift > 0 && off+i+1 < unpaddedChunk {
+				b |= in[off+i+1] << (8 - shift)
+			}
+			out[q*NodeSize+i] = b
+		}
+		out[q*NodeSize+NodeSize-1] &= 0x3f
+	}
+}
+
+// Calc calculates the piece commitment of the data written to it. It
+// implements hash.Hash, and its zero value is ready to use.
+//
+// The data is zero-padded to the next unpadded piece size (127 times a
+// power of two, and at least 127 bytes), fr32 padded, and split into 32-byte
+// leaves; the commitment is the root of the binary tree over those leaves.
+// Only the nodes on the right edge of the tree are kept in memory.
+type Calc struct {
+	buf    [unpaddedChunk]byte
+	n      int
+	leaves uint64
+	// stack holds the roots of the complete subtrees written so far, largest
+	// first. There is one for every bit set in leaves.
+	stack [][NodeSize]byte
+}
+
+var _ hash.Hash = (*Calc)(nil)
+
+// Size returns NodeSize.
+func (c *Calc) Size() int { return NodeSize }
+
+// BlockSize returns the size of the chunks fr32 padding works on.
+func (c *Calc) BlockSize() int { return unpaddedChunk }
+
+// Reset discards the data written so far.
+func (c *Calc) Reset() {
+	c.n = 0
+	c.leaves = 0
+	c.stack = c.stack[:0]
+}
+
+// Write adds data to the piece.
+func (c *Calc) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		k := copy(c.buf[c.n:], p)
+		c.n += k
+		p = p[k:]
+		if c.n == unpaddedChunk {
+			c.chunk()
+		}
+	}
+	return written, nil
+}
+
+func (c *Calc) chunk() {
+	var padded [paddedChunk]byte
+	fr32Pad(&padded, &c.buf)
+	for i := 0; i < paddedChunk; i += NodeSize {
+		c.addLeaf([NodeSize]byte(padded[i : i+NodeSize]))
+	}
+	c.n = 0
+}
+
+func (c *Calc) addLeaf(node [NodeSize]byte) {
+	for level := 0; c.leaves>>level&1 == 1; level++ {
+		node = NodeHash(c.stack[len(c.stack)-1], node)
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+	c.stack = append(c.stack, node)
+	c.leaves++
+}
+
+// Sum appends the piece commitment of the data written so far to b. It does
+// not change the state of the Calc.
+func (c *Calc) Sum(b []byte) []byte {
+	commP, _ := c.Digest()
+	return append(b, commP[:]...)
+}
+
+// Digest returns the piece commitment of the data written so far, and the
+// size of the padded piece it commits to.
+func (c *Calc) Digest() (commP [NodeSize]byte, paddedPieceSize uint64) {
+	dup := *c
+	dup.stack = append([][NodeSize]byte(nil), c.stack...)
+	if dup.n > 0 || dup.leaves == 0 {
+		clear(dup.buf[dup.n:])
+		dup.chunk()
+	}
+
+	height := bits.Len64(dup.leaves - 1)
+	var cur *[NodeSize]byte
+	for level := 0; level < height; level++ {
+		if dup.leaves>>level&1 == 1 {
+			left := dup.stack[len(dup.stack)-1]
+			dup.stack = dup.stack[:len(dup.stack)-1]
+			if cur == nil {
+				cur = &zeroComms[level]
+			}
+			parent := NodeHash(left, *cur)
+			cur = &parent
+		} else if cur != nil {
+			parent := NodeHash(*cur, zeroComms[level])
+			cur = &parent
+		}
+	}
+	if cur == nil {
+		cur = &dup.stack[0]
+	}
+	return *cur, NodeSize << height
+}
+
+// SumPiece reads r to the end and returns the piece commitment of what it
+// read, and the size of the padded piece it commits to.
+func SumPiece(r io.Reader) (commP [NodeSize]byte, paddedPieceSize uint64, err error) {
+	var c Calc
+	if _, err := io.Copy(&c, r); err != nil {
+		return commP, 0, err
+	}
+	commP, paddedPieceSize = c.Digest()
+	return commP, paddedPieceSize, nil
+}
+
+// ZeroPieceCommitment returns the commitment of a padded piece of the given
+// size, a power of two of at least 128 bytes, holding only zeros.
+func ZeroPieceCommitment(paddedPieceSize uint64) ([NodeSize]byte, bool) {
+	if paddedPieceSize < paddedChunk || paddedPieceSize&(paddedPieceSize-1) != 0 {
+		return [NodeSize]byte{}, false
+	}
+	return zeroComms[bits.TrailingZeros64(paddedPieceSize/NodeSize)], true
+}
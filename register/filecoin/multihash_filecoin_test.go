@@ -0,0 +1,208 @@
+package filecoin
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Commitments of all-zero pieces, as published in their CIDs: the unsealed
+// sector CIDs of an empty 2KiB sector
+// (baga6ea4seaqpy7usqklokfx2vxuynmupslkeutzexe2uqurdg5vhtebhxqmpqmy) and of an
+// empty 32GiB sector
+// (baga6ea4seaqao7s73y24kcutaosvacpdjgfe5pw76ooefnyqw4ynr3d2y6x2mpq).
+var zeroPieces = []struct {
+	paddedPieceSize uint64
+	commP           string
+}{
+	{128, "3731bb99ac689f66eef5973e4a94da188f4ddcae580724fc6f3fd60dfd488333"},
+	{2 << 10, "fc7e928296e516faade986b28f92d44a4f24b935485223376a799027bc18f833"},
+	{32 << 30, "077e5fde35c50a9303a55009e3498a4ebedff39c42b710b730d8ec7ac7afa63e"},
+}
+
+func TestNodeHash(t *testing.T) {
+	// sha2-256 of 64 zero bytes is f5a5...fb4b.
+	expected := mustHexDecode("f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb0b")
+	if result := NodeHash([NodeSize]byte{}, [NodeSize]byte{}); !bytes.Equal(result[:], expected) {
+		t.Errorf("expected %x; got %x", expected, result)
+	}
+}
+
+func TestZeroPieceCommitment(t *testing.T) {
+	for _, tc := range zeroPieces {
+		commP, ok := ZeroPieceCommitment(tc.paddedPieceSize)
+		if !ok {
+			t.Fatalf("%d: not a valid piece size", tc.paddedPieceSize)
+		}
+		if result := hex.EncodeToString(commP[:]); result != tc.commP {
+			t.Errorf("%d: expected %s; got %s", tc.paddedPieceSize, tc.commP, result)
+		}
+	}
+	for _, size := range []uint64{0, 64, 129, 3 << 10} {
+		if _, ok := ZeroPieceCommitment(size); ok {
+			t.Errorf("%d: expected an invalid piece size", size)
+		}
+	}
+
+	// Streaming zeros must give the same commitment.
+	tc := zeroPieces[1]
+	commP, size, err := SumPiece(io.LimitReader(zeroReader{}, int64(tc.paddedPieceSize/128*127)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := hex.EncodeToString(commP[:]); result != tc.commP || size != tc.paddedPieceSize {
+		t.Errorf("expected %s (%d); got %s (%d)", tc.commP, tc.paddedPieceSize, result, size)
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestFr32Pad(t *testing.T) {
+	var in [unpaddedChunk]byte
+	for i := range in {
+		in[i] = 0xff
+	}
+	var out [paddedChunk]byte
+	fr32Pad(&out, &in)
+	for i, b := range out {
+		expected := byte(0xff)
+		if i%NodeSize == NodeSize-1 {
+			expected = 0x3f
+		}
+		if b != expected {
+			t.Fatalf("byte %d: expected %02x; got %02x", i, expected, b)
+		}
+	}
+}
+
+// Piece CIDs computed by Lotus (ClientCalcCommP), as published in the
+// testdata of github.com/filecoin-project/go-fil-commp-hashhash v0.2.0:
+// payloads of repeated 0xCC bytes from testdata/0xCC.txt, and payloads of
+// jbenet/go-random output with seed 1337 from testdata/random.txt.
+var lotusPieces = []struct {
+	payload         string
+	size            int
+	paddedPieceSize uint64
+	cid             string
+}{
+	{"0xCC", 96, 128, "baga6ea4seaqhwcjhi4krhl3ht6dewnwevkpxbepxy7p7onwgz65t52typbsysby"},
+	{"0xCC", 126, 128, "baga6ea4seaqapbh46gdnszvb7fcinevsy5bzg3b4higkh7groptswf6zas6jamy"},
+	{"0xCC", 127, 128, "baga6ea4seaqmfldjtozgne6adk7eve2vdxte7vzlivae7nzsbrawobo546zkijq"},
+	{"0xCC", 192, 256, "baga6ea4seaqkx7m2s6r4zahtlbwrs5ryvemkclwfp7nijopdd5swpdnxzjf7wkq"},
+	{"0xCC", 253, 256, "baga6ea4seaql6ldbyafhiecr36xba5tufreyo4km2ts3lfknhl2zogp3aztxijy"},
+	{"0xCC", 254, 256, "baga6ea4seaqkixbzz75uys2pcjbrbdilgjhmum72qm4xphrwav2iyel5oat4aka"},
+	{"0xCC", 255, 512, "baga6ea4seaqg7celu5y2iwbi2ra5koygvotxtzr5lj6vzvxi6gfub6mpa6niwpi"},
+	{"0xCC", 256, 512, "baga6ea4seaqi7c3dnwkqysqh4lpkz5jaxz2d2f5bvo3ttu2hnfmdewhcoji56na"},
+	{"0xCC", 384, 512, "baga6ea4seaqhexlmnzbarsbdbdahs7e36dkq5vkdwsrttehoakrif5wiqme36lq"},
+	{"0xCC", 507, 512, "baga6ea4seaqenvh5mcy5cjqwsbubbpczprkk2onwvfjd2743zkqh6ofuzkatwey"},
+	{"0xCC", 508, 512, "baga6ea4seaqb6ckbupixkhwp7thgb52f4en222boppajkqk7gaomkpof3lh4cei"},
+	{"0xCC", 509, 1024, "baga6ea4seaqdzbeaexq6gpbqh2tlnbz5mm5neap2kejsketkogzd6x2dx7dzkii"},
+	{"0xCC", 512, 1024, "baga6ea4seaqojaa522sjqms2wipasjbxnjgytunsgp52tgrfcofj73f7q7ou6hy"},
+	{"0xCC", 768, 1024, "baga6ea4seaqb6xvxaybzp6vslujjiwvgt23ckrwt7y53eddy5qmc6csnc37lwpi"},
+	{"0xCC", 1015, 1024, "baga6ea4seaqmgiyjcutwgo6glks2mogixs6mb4sbehto6uzienucfx23wbtkica"},
+	{"0xCC", 1016, 1024, "baga6ea4seaqjxgfdkdu37aryhg7bqqiwizj5f6ugasftgeocabwnj4cxkgisaoq"},
+	{"0xCC", 1017, 2048, "baga6ea4seaqf3n5ob5qonkwnxfcbjzftsagbnrjfzualqvzhcylz46b7sgz6wmi"},
+	{"0xCC", 1024, 2048, "baga6ea4seaqdlpnhgsndrgjeu4p46hahlsr4lybg6du4d56ooppdpxhcofxeuoi"},
+	{"random", 130048, 131072, "baga6ea4seaqbsqvzsxzji22cqooypgdagqytcc47dr4ackodmoi55pqipnxacca"},
+	{"random", 130049, 262144, "baga6ea4seaqczo7lan535elusohyfhnod3macveiryljwlt3ylv77fkxleno4ni"},
+	{"random", 196608, 262144, "baga6ea4seaqk6nmrw5btcsnd5tjxmrp64sjfisohz753ut56aseze536bwggqmq"},
+	{"random", 1040385, 2097152, "baga6ea4seaqmdlrzmi444jlcev7wr76wijaixvrestdlcm4me7u4nvjmphyuafq"},
+}
+
+// lotusPayload regenerates the payload of a lotusPieces entry. go-random
+// writes the little-endian bytes of successive math/rand Uint32 values.
+func lotusPayload(payload string, size int) []byte {
+	data := make([]byte, size)
+	switch payload {
+	case "0xCC":
+		for i := range data {
+			data[i] = 0xcc
+		}
+	case "random":
+		rng := rand.New(rand.NewSource(1337))
+		for i := 0; i < size; i += 4 {
+			var word [4]byte
+			binary.LittleEndian.PutUint32(word[:], rng.Uint32())
+			copy(data[i:], word[:])
+		}
+	}
+	return data
+}
+
+// pieceCIDDigest returns the commitment in a piece CID: the last 32 bytes of
+// a base32 CIDv1 whose multihash is sha2-256-trunc254-padded.
+func pieceCIDDigest(cid string) []byte {
+	raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(cid[1:]))
+	if err != nil {
+		panic(err)
+	}
+	return raw[len(raw)-NodeSize:]
+}
+
+func TestCalc(t *testing.T) {
+	for _, tc := range lotusPieces {
+		data := lotusPayload(tc.payload, tc.size)
+		expected := pieceCIDDigest(tc.cid)
+
+		h, err := multihash.GetHasher(multihash.SHA2_256_TRUNC254_PADDED)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write in uneven pieces to exercise the chunk buffering.
+		for i := 0; i < len(data); i += 50 {
+			h.Write(data[i:min(i+50, len(data))])
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, expected) {
+			t.Errorf("%s, %d bytes: expected %x; got %x", tc.payload, tc.size, expected, result)
+		}
+		if _, size := h.(*Calc).Digest(); size != tc.paddedPieceSize {
+			t.Errorf("%s, %d bytes: expected a %d byte piece; got %d", tc.payload, tc.size, tc.paddedPieceSize, size)
+		}
+
+		commP, _, err := SumPiece(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(commP[:], expected) {
+			t.Errorf("%s, %d bytes: SumPiece: expected %x; got %x", tc.payload, tc.size, expected, commP)
+		}
+	}
+}
+
+func TestCalcSumDoesNotChangeState(t *testing.T) {
+	var c, c2 Calc
+	data := bytes.Repeat([]byte("abc"), 200)
+	c.Write(data[:300])
+	c.Sum(nil)
+	c.Write(data[300:])
+	c2.Write(data)
+	if !bytes.Equal(c.Sum(nil), c2.Sum(nil)) {
+		t.Error("writing after Sum gave a different commitment")
+	}
+
+	c.Reset()
+	var empty Calc
+	if !bytes.Equal(c.Sum(nil), empty.Sum(nil)) {
+		t.Error("Reset did not clear the state")
+	}
+}
@@ -0,0 +1,199 @@
+package k12
+
+import (
+	"hash"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/multiformats/go-multihash/internal/keccak"
+)
+
+// TurboSHAKE is Keccak-p[1600] reduced to its last 12 rounds.
+const turboRounds = 12
+
+const (
+	rate128 = 168
+	rate256 = 136
+)
+
+// chunkSize is the size of the leaves of the KangarooTwelve tree.
+const chunkSize = 8192
+
+// Domain separation bytes.
+const (
+	dsSingle = 0x07
+	dsLeaf   = 0x0b
+	dsFinal  = 0x06
+)
+
+// cvSize is the size of a leaf's chaining value.
+const cvSize = 32
+
+// NewTurboSHAKE128 returns TurboSHAKE128 with domain separation byte d, which
+// must be between 0x01 and 0x7f, and a digest of size bytes. The result also
+// implements io.Reader, to be used as an extendable-output function once
+// writing is done.
+func NewTurboSHAKE128(d byte, size int) hash.Hash {
+	return keccak.New(rate128, d, turboRounds, size)
+}
+
+// NewTurboSHAKE256 is like NewTurboSHAKE128, for TurboSHAKE256.
+func NewTurboSHAKE256(d byte, size int) hash.Hash {
+	return keccak.New(rate256, d, turboRounds, size)
+}
+
+// Hasher computes KangarooTwelve (KT128), with a customization string. Its
+// Sum returns Size bytes; XOF gives access to the whole output stream.
+//
+// Inputs longer than one chunk are hashed as a tree, whose leaves are
+// hashed in parallel when a single Write spans several of them.
+type Hasher struct {
+	custom []byte
+	size   int
+
+	// chunk holds the chunk being written. The first chunk is kept until
+	// more data follows it, as a message of a single chunk is not hashed as
+	// a tree.
+	chunk [chunkSize]byte
+	n     int
+	// chunks counts the chunks completed so far.
+	chunks uint64
+	// final absorbs the final node, once there is more than one chunk.
+	final *keccak.Sponge
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+// New returns KangarooTwelve with the given digest size and customization
+// string, which may be empty.
+func New(size int, customization []byte) *Hasher {
+	return &Hasher{
+		custom: append([]byte(nil), customization...),
+		size:   size,
+	}
+}
+
+// Size returns the number of bytes Sum returns.
+func (h *Hasher) Size() int { return h.size }
+
+// BlockSize returns the rate of TurboSHAKE128.
+func (h *Hasher) BlockSize() int { return rate128 }
+
+// Reset discards the data written so far.
+func (h *Hasher) Reset() {
+	h.n = 0
+	h.chunks = 0
+	h.final = nil
+}
+
+// Write adds data to the message.
+func (h *Hasher) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		if h.n == chunkSize {
+			h.endChunk()
+		}
+		if h.n == 0 && h.final != nil && len(p) >= chunkSize {
+			// Hash whole leaves straight from p.
+			k := len(p) / chunkSize
+			h.leaves(p[:k*chunkSize])
+			p = p[k*chunkSize:]
+			continue
+		}
+		k := copy(h.chunk[h.n:], p)
+		h.n += k
+		p = p[k:]
+	}
+	return written, nil
+}
+
+// endChunk processes the full chunk in the buffer.
+func (h *Hasher) endChunk() {
+	if h.final == nil {
+		h.final = keccak.New(rate128, dsFinal, turboRounds, h.size)
+		h.final.Write(h.chunk[:h.n])
+		h.final.Write([]byte{0x03, 0, 0, 0, 0, 0, 0, 0})
+		h.chunks++
+	} else {
+		h.leaves(h.chunk[:h.n])
+	}
+	h.n = 0
+}
+
+// leaves absorbs the chaining values of consecutive leaves into the final
+// node. All but the last leaf must be full.
+func (h *Hasher) leaves(data []byte) {
+	n := (len(data) + chunkSize - 1) / chunkSize
+	cvs := make([]byte, n*cvSize)
+	leaf := func(i int) {
+		s := keccak.New(rate128, dsLeaf, turboRounds, cvSize)
+		s.Write(data[i*chunkSize : min((i+1)*chunkSize, len(data))])
+		s.Read(cvs[i*cvSize : (i+1)*cvSize])
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), n)
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			leaf(i)
+		}
+	} else {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := w; i < n; i += workers {
+					leaf(i)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+
+	h.final.Write(cvs)
+	h.chunks += uint64(n)
+}
+
+// XOF returns a reader over the output of KangarooTwelve for the data
+// written so far, of which Sum returns the first Size bytes. It does not
+// change the state of the Hasher.
+func (h *Hasher) XOF() io.Reader {
+	dup := *h
+	if h.final != nil {
+		final := *h.final
+		dup.final = &final
+	}
+	dup.Write(h.custom)
+	dup.Write(lengthEncode(uint64(len(h.custom))))
+
+	if dup.final == nil {
+		s := keccak.New(rate128, dsSingle, turboRounds, h.size)
+		s.Write(dup.chunk[:dup.n])
+		return s
+	}
+	if dup.n > 0 {
+		dup.endChunk()
+	}
+	// The first chunk is part of the final node, not a leaf.
+	dup.final.Write(lengthEncode(dup.chunks - 1))
+	dup.final.Write([]byte{0xff, 0xff})
+	return dup.final
+}
+
+// Sum appends the first Size bytes of output to b.
+func (h *Hasher) Sum(b []byte) []byte {
+	out := make([]byte, h.size)
+	io.ReadFull(h.XOF(), out)
+	return append(b, out...)
+}
+
+// lengthEncode encodes x as its big-endian bytes without leading zeros,
+// followed by their number.
+func lengthEncode(x uint64) []byte {
+	var b []byte
+	for ; x > 0; x >>= 8 {
+		b = append([]byte{byte(x)}, b...)
+	}
+	return append(b, byte(len(b)))
+}
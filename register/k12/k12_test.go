@@ -0,0 +1,143 @@
+package k12
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+// ptn returns n bytes of the repeating 0x00..0xfa pattern used by the
+// KangarooTwelve test vectors.
+func ptn(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func pow(b, e int) int {
+	r := 1
+	for ; e > 0; e-- {
+		r *= b
+	}
+	return r
+}
+
+// Test vectors from RFC 9861, section 5.
+var vectors = []struct {
+	message, custom []byte
+	expected        string
+}{
+	{nil, nil, "1ac2d450fc3b4205d19da7bfca1b37513c0803577ac7167f06fe2ce1f0ef39e5"},
+	{ptn(pow(17, 1)), nil, "6bf75fa2239198db4772e36478f8e19b0f371205f6a9a93a273f51df37122888"},
+	{ptn(pow(17, 2)), nil, "0c315ebcdedbf61426de7dcf8fb725d1e74675d7f5327a5067f367b108ecb67c"},
+	{ptn(pow(17, 3)), nil, "cb552e2ec77d9910701d578b457ddf772c12e322e4ee7fe417f92c758f0d59d0"},
+	{ptn(pow(17, 4)), nil, "8701045e22205345ff4dda05555cbb5c3af1a771c2b89baef37db43d9998b9fe"},
+	{ptn(pow(17, 5)), nil, "844d610933b1b9963cbdeb5ae3b6b05cc7cbd67ceedf883eb678a0a8e0371682"},
+	{ptn(pow(17, 6)), nil, "3c390782a8a4e89fa6367f72feaaf13255c8d95878481d3cd8ce85f58e880af8"},
+	{nil, ptn(1), "fab658db63e94a246188bf7af69a133045f46ee984c56e3c3328caaf1aa1a583"},
+	{[]byte{0xff}, ptn(41), "d848c5068ced736f4462159b9867fd4c20b808acc3d5bc48e0b06ba0a3762ec4"},
+	{bytes.Repeat([]byte{0xff}, 3), ptn(pow(41, 2)), "c389e5009ae57120854c2e8c64670ac01358cf4c1baf89447a724234dc7ced74"},
+	{bytes.Repeat([]byte{0xff}, 7), ptn(pow(41, 3)), "75d2f86a2e644566726b4fbcfc5657b9dbcf070c7b0dca06450ab291d7443bcf"},
+}
+
+func TestKangarooTwelve(t *testing.T) {
+	for _, v := range vectors {
+		name := fmt.Sprintf("%d/%d", len(v.message), len(v.custom))
+		t.Run(name, func(t *testing.T) {
+			h := New(32, v.custom)
+			h.Write(v.message)
+			if result := hex.EncodeToString(h.Sum(nil)); result != v.expected {
+				t.Errorf("expected %s; got %s", v.expected, result)
+			}
+
+			// Writing in pieces, across chunk boundaries, gives the same result.
+			h.Reset()
+			for i := 0; i < len(v.message); i += 5000 {
+				h.Write(v.message[i:min(i+5000, len(v.message))])
+			}
+			if result := hex.EncodeToString(h.Sum(nil)); result != v.expected {
+				t.Errorf("in pieces: expected %s; got %s", v.expected, result)
+			}
+		})
+	}
+}
+
+func TestChunkBoundaries(t *testing.T) {
+	// Around the single chunk limit and the first leaves, writing one byte at
+	// a time must agree with writing everything at once.
+	for _, n := range []int{chunkSize - 1, chunkSize, chunkSize + 1, 2 * chunkSize, 3*chunkSize + 1} {
+		data := ptn(n)
+		all := New(32, nil)
+		all.Write(data)
+		single := New(32, nil)
+		for i := range data {
+			single.Write(data[i : i+1])
+		}
+		if !bytes.Equal(all.Sum(nil), single.Sum(nil)) {
+			t.Errorf("%d bytes: digest depends on how the data was written", n)
+		}
+	}
+}
+
+func TestXOF(t *testing.T) {
+	h := New(32, nil)
+	long := make([]byte, 10032)
+	io.ReadFull(h.XOF(), long)
+	// RFC 9861: the last 32 bytes of a 10032 byte output for an empty message.
+	expected := "e8dc563642f7228c84684c898405d3a834799158c079b12880277a1d28e2ff6d"
+	if result := hex.EncodeToString(long[10000:]); result != expected {
+		t.Errorf("expected %s; got %s", expected, result)
+	}
+	if !bytes.Equal(long[:32], h.Sum(nil)) {
+		t.Error("Sum is not a prefix of the XOF output")
+	}
+}
+
+func TestTurboSHAKE(t *testing.T) {
+	for _, tc := range []struct {
+		h        hash.Hash
+		expected string
+	}{
+		// RFC 9861, section 5.
+		{NewTurboSHAKE128(0x1f, 32), "1e415f1c5983aff2169217277d17bb538cd945a397ddec541f1ce41af2c1b74c"},
+		{NewTurboSHAKE256(0x1f, 64), "367a329dafea871c7802ec67f905ae13c57695dc2c6663c61035f59a18f8e7db11edc0e12e91ea60eb6b32df06dd7f002fbafabb6e13ec1cc20d995547600db0"},
+	} {
+		if result := hex.EncodeToString(tc.h.Sum(nil)); result != tc.expected {
+			t.Errorf("expected %s; got %s", tc.expected, result)
+		}
+	}
+}
+
+func TestRegistered(t *testing.T) {
+	h, err := multihash.GetVariableHasher(multihash.KANGAROOTWELVE, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := hex.EncodeToString(h.Sum(nil)); result != vectors[0].expected {
+		t.Errorf("expected %s; got %s", vectors[0].expected, result)
+	}
+	h, err = multihash.GetVariableHasher(multihash.KANGAROOTWELVE, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := hex.EncodeToString(h.Sum(nil)); result[:64] != vectors[0].expected {
+		t.Errorf("expected a 64 byte digest starting %s; got %s", vectors[0].expected, result)
+	}
+	h, err = multihash.GetVariableHasher(multihash.KANGAROOTWELVE, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := h.Sum(nil); len(result) != 0 {
+		t.Errorf("expected an empty digest; got %x", result)
+	}
+	if _, err := multihash.GetVariableHasher(multihash.KANGAROOTWELVE, MaxSize+1); err == nil {
+		t.Error("expected an error above MaxSize")
+	}
+}
@@ -0,0 +1,41 @@
+/*
+This package has no purpose except to register the KangarooTwelve hash
+function.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/k12"
+	)
+
+KangarooTwelve (KT128) is an extendable-output function built on
+TurboSHAKE128, the Keccak sponge with 12 rounds instead of 24. The multihash
+uses an empty customization string; New accepts others, and
+NewTurboSHAKE128 and NewTurboSHAKE256 expose TurboSHAKE itself.
+*/
+package k12
+
+import (
+	"hash"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+// DefaultSize is the size of the registered multihash digest, in bytes.
+const DefaultSize = 32
+
+// MaxSize is the largest digest size, in bytes, the registered hasher
+// produces. KangarooTwelve itself has no limit.
+const MaxSize = 128
+
+func init() {
+	multihash.RegisterVariableSize(multihash.KANGAROOTWELVE, func(size int) (hash.Hash, bool) {
+		if size == -1 {
+			size = DefaultSize
+		} else if size > MaxSize || size < 0 {
+			return nil, false
+		}
+		return New(size, nil), true
+	})
+	multihash.RegisterStrength(multihash.KANGAROOTWELVE, multihash.Strength{Bits: 128, Cryptographic: true})
+}
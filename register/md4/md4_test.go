@@ -0,0 +1,46 @@
+package md4
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestMD4(t *testing.T) {
+	// RFC 1320, appendix A.5.
+	for _, tc := range []struct {
+		data     string
+		expected []byte
+	}{
+		{"", mustHexDecode("31d6cfe0d16ae931b73c59d7e0c089c0")},
+		{"a", mustHexDecode("bde52cb31de33e46245e05fbdbd6fb24")},
+		{"abc", mustHexDecode("a448017aaf21d8525fc10ae87aa6729d")},
+		{"message digest", mustHexDecode("d9130a8164549fe818874806e1c7014b")},
+		{"abcdefghijklmnopqrstuvwxyz", mustHexDecode("d79e1c308aa5bbcdeea8ed63df412da9")},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", mustHexDecode("e33b4ddc9c38f2199c3e7b164fcc0536")},
+	} {
+		h, err := multihash.GetHasher(multihash.MD4)
+		if err != nil {
+			t.Fatalf("failed to get: %s", err)
+		}
+		h.Write([]byte(tc.data))
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("%q: expected %x; got %x", tc.data, tc.expected, result)
+		}
+	}
+
+	s, ok := multihash.GetStrength(multihash.MD4)
+	if !ok || !s.Broken {
+		t.Error("md4 must be registered as broken")
+	}
+}
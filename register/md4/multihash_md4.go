@@ -0,0 +1,28 @@
+/*
+This package has no purpose except to register the md4 hash function.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/md4"
+	)
+
+md4 is broken: collisions take a handful of hash computations. It is only
+registered to identify and check digests from existing systems, such as
+ed2k links. Like md5 and sha1, it is registered as a broken cryptographic
+hash: VerifyAny in the root package prefers any sound cryptographic hash
+over it, but still ranks it above non-cryptographic ones such as murmur3 and
+xxHash. Any MinBits policy above 2 excludes it.
+*/
+package md4
+
+import (
+	"golang.org/x/crypto/md4"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	multihash.Register(multihash.MD4, md4.New)
+	multihash.RegisterStrength(multihash.MD4, multihash.Strength{Bits: 2, Cryptographic: true, Broken: true})
+}
@@ -7,7 +7,9 @@ It is meant to be used as a side-effecting import, e.g.
 		_ "github.com/multiformats/go-multihash/register/murmur3"
 	)
 
-This package registers multihashes for murmur3
+This package registers multihashes for murmur3: murmur3-32,
+murmur3-x64-64 and murmur3-x64-128. They are registered with a seed of 0;
+New32, New64 and New128 take other seeds.
 */
 package murmur3
 
@@ -19,7 +21,40 @@ import (
 )
 
 func init() {
-	multihash.Register(multihash.MURMUR3X64_64, func() hash.Hash { return murmur64{murmur3.New64()} })
+	RegisterSeed(0)
+
+	multihash.RegisterStrength(multihash.MURMUR3_32, multihash.Strength{Bits: 16})
+	multihash.RegisterStrength(multihash.MURMUR3X64_64, multihash.Strength{Bits: 32})
+	multihash.RegisterStrength(multihash.MURMUR3X64_128, multihash.Strength{Bits: 64})
+}
+
+// RegisterSeed replaces the registered murmur3 hashers with ones using seed.
+// The package registers them with a seed of 0; to hash with another seed
+// without changing the registry, use New32, New64 or New128.
+//
+// Multihashes do not record the seed, so every party must agree on it. Like
+// the Register function of the core package, RegisterSeed has a global
+// effect and should only be used at init time.
+func RegisterSeed(seed uint32) {
+	multihash.Register(multihash.MURMUR3_32, func() hash.Hash { return New32(seed) })
+	multihash.Register(multihash.MURMUR3X64_64, func() hash.Hash { return New64(seed) })
+	multihash.Register(multihash.MURMUR3X64_128, func() hash.Hash { return New128(seed) })
+}
+
+// New32 returns murmur3-32 with the given seed.
+func New32(seed uint32) hash.Hash32 {
+	return murmur3.New32WithSeed(seed)
+}
+
+// New64 returns murmur3-x64-64, the first half of murmur3-x64-128, with the
+// given seed.
+func New64(seed uint32) hash.Hash64 {
+	return murmur64{murmur3.New64WithSeed(seed)}
+}
+
+// New128 returns murmur3-x64-128 with the given seed.
+func New128(seed uint32) hash.Hash {
+	return murmur3.New128WithSeed(seed)
 }
 
 // A wrapper is needed to export the correct size, because murmur3 incorrectly advertises Hash64 as a 128bit hash.
@@ -0,0 +1,114 @@
+package murmur3
+
+import (
+	"encoding/binary"
+	"hash"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+// verification computes SMHasher's verification value for a hash, given a
+// function returning its digest in SMHasher's byte order: the digests of the
+// keys {}, {0}, {0, 1}, ... {0, ..., 254}, with seeds 256 down to 1, are
+// hashed together with seed 0, and the value is the first four bytes of the
+// result as a little-endian integer.
+func verification(sum func(data []byte, seed uint32) []byte) uint32 {
+	key := make([]byte, 256)
+	var hashes []byte
+	for i := 0; i < 256; i++ {
+		key[i] = byte(i)
+		hashes = append(hashes, sum(key[:i], uint32(256-i))...)
+	}
+	return binary.LittleEndian.Uint32(sum(hashes, 0))
+}
+
+// smhasherOrder returns the digest of data, by the constructor of the given
+// width, in SMHasher's little-endian byte order.
+func smhasherOrder(newHash func(seed uint32) hash.Hash) func(data []byte, seed uint32) []byte {
+	return func(data []byte, seed uint32) []byte {
+		h := newHash(seed)
+		h.Write(data)
+		sum := h.Sum(nil)
+		// The multihash digests are big-endian, per 32 or 64-bit word.
+		word := 8
+		if len(sum) == 4 {
+			word = 4
+		}
+		for i := 0; i < len(sum); i += word {
+			w := sum[i : i+word]
+			for j := 0; j < word/2; j++ {
+				w[j], w[word-1-j] = w[word-1-j], w[j]
+			}
+		}
+		return sum
+	}
+}
+
+func TestSMHasherVerification(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		newHash  func(seed uint32) hash.Hash
+		expected uint32
+	}{
+		// Murmur3A and Murmur3F in SMHasher.
+		{"murmur3-32", func(seed uint32) hash.Hash { return New32(seed) }, 0xb0f57ee3},
+		{"murmur3-x64-128", New128, 0x6384ba69},
+	} {
+		if result := verification(smhasherOrder(tc.newHash)); result != tc.expected {
+			t.Errorf("%s: expected verification value %08x; got %08x", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestSizes(t *testing.T) {
+	for code, size := range map[uint64]int{
+		multihash.MURMUR3_32:     4,
+		multihash.MURMUR3X64_64:  8,
+		multihash.MURMUR3X64_128: 16,
+	} {
+		h, err := multihash.GetHasher(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Size() != size || len(h.Sum(nil)) != size {
+			t.Errorf("0x%x: expected %d bytes; Size is %d and Sum has %d", code, size, h.Size(), len(h.Sum(nil)))
+		}
+		if multihash.DefaultLengths[code] != size {
+			t.Errorf("0x%x: expected a default length of %d; got %d", code, size, multihash.DefaultLengths[code])
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	sum := func(h hash.Hash) []byte {
+		h.Write([]byte("foo"))
+		return h.Sum(nil)
+	}
+	for _, tc := range []struct {
+		code    uint64
+		newHash func(seed uint32) hash.Hash
+	}{
+		{multihash.MURMUR3_32, func(seed uint32) hash.Hash { return New32(seed) }},
+		{multihash.MURMUR3X64_64, func(seed uint32) hash.Hash { return New64(seed) }},
+		{multihash.MURMUR3X64_128, New128},
+	} {
+		// The registered hashers use a seed of 0.
+		h, err := multihash.GetHasher(tc.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		registered := sum(h)
+		if unseeded := sum(tc.newHash(0)); string(unseeded) != string(registered) {
+			t.Errorf("0x%x: expected the registered hasher to use a seed of 0", tc.code)
+		}
+		if seeded := sum(tc.newHash(42)); string(seeded) == string(registered) {
+			t.Errorf("0x%x: the seed was ignored", tc.code)
+		}
+	}
+
+	// murmur3-x64-64 is the first half of murmur3-x64-128.
+	if half, full := sum(New64(42)), sum(New128(42)); string(full[:8]) != string(half) {
+		t.Errorf("expected %x to start with %x", full, half)
+	}
+}
@@ -0,0 +1,139 @@
+package poseidon
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// element is a member of the scalar field of BLS12-381, in Montgomery form
+// with R = 2^256, as little-endian 64-bit limbs. Apart from inversion,
+// which only derives constants, the arithmetic does not branch on or index
+// by the values.
+type element [4]uint64
+
+// modulus is r, the order of the BLS12-381 scalar field.
+var modulus = element{0xffffffff00000001, 0x53bda402fffe5bfe, 0x3339d80809a1d805, 0x73eda753299d7d48}
+
+// qInv is -r^-1 mod 2^64.
+const qInv = 0xfffffffeffffffff
+
+var (
+	// rOne is R modulo r, one in Montgomery form.
+	rOne = element{0x00000001fffffffe, 0x5884b7fa00034802, 0x998c4fefecbc4ff5, 0x1824b159acc5056f}
+	// rSquare is R^2 modulo r, which converts to Montgomery form.
+	rSquare = element{0xc999e990f3f29c6d, 0x2b6cedcb87925c23, 0x05d314967254398f, 0x0748d9d99f59ff11}
+)
+
+// reduce subtracts the modulus from hi:z, a value below twice the modulus,
+// if it is not below the modulus.
+func (z *element) reduce(hi uint64) {
+	var t element
+	var b uint64
+	t[0], b = bits.Sub64(z[0], modulus[0], 0)
+	t[1], b = bits.Sub64(z[1], modulus[1], b)
+	t[2], b = bits.Sub64(z[2], modulus[2], b)
+	t[3], b = bits.Sub64(z[3], modulus[3], b)
+	mask := -(hi | b ^ 1)
+	for i := range z {
+		z[i] = z[i]&^mask | t[i]&mask
+	}
+}
+
+func (z *element) add(x, y *element) {
+	var c uint64
+	z[0], c = bits.Add64(x[0], y[0], 0)
+	z[1], c = bits.Add64(x[1], y[1], c)
+	z[2], c = bits.Add64(x[2], y[2], c)
+	z[3], c = bits.Add64(x[3], y[3], c)
+	z.reduce(c)
+}
+
+// mul sets z to x*y/R, by coarsely integrated operand scanning.
+func (z *element) mul(x, y *element) {
+	var t [6]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(x[j], y[i])
+			var cc uint64
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j], c = lo, hi
+		}
+		var cc uint64
+		t[4], cc = bits.Add64(t[4], c, 0)
+		t[5] = cc
+
+		m := t[0] * qInv
+		hi, lo := bits.Mul64(m, modulus[0])
+		_, cc = bits.Add64(lo, t[0], 0)
+		c = hi + cc
+		for j := 1; j < 4; j++ {
+			hi, lo := bits.Mul64(m, modulus[j])
+			lo, cc = bits.Add64(lo, t[j], 0)
+			hi += cc
+			lo, cc = bits.Add64(lo, c, 0)
+			hi += cc
+			t[j-1], c = lo, hi
+		}
+		t[3], cc = bits.Add64(t[4], c, 0)
+		t[4] = t[5] + cc
+	}
+	*z = element{t[0], t[1], t[2], t[3]}
+	z.reduce(t[4])
+}
+
+// pow5 sets z to x^5, the S-box of Poseidon over this field.
+func (z *element) pow5(x *element) {
+	var x2, x4 element
+	x2.mul(x, x)
+	x4.mul(&x2, &x2)
+	z.mul(&x4, x)
+}
+
+// inverse sets z to x^(r-2), the inverse of a non-zero x.
+func (z *element) inverse(x *element) {
+	e := modulus
+	e[0] -= 2
+	r := rOne
+	for i := 255; i >= 0; i-- {
+		r.mul(&r, &r)
+		if e[i/64]>>(i%64)&1 == 1 {
+			r.mul(&r, x)
+		}
+	}
+	*z = r
+}
+
+func (z *element) setUint64(v uint64) {
+	*z = element{v}
+	z.mul(z, &rSquare)
+}
+
+// setBytes sets z to the little-endian integer b, reporting whether it is
+// below the modulus, that is, a canonical encoding.
+func (z *element) setBytes(b *[32]byte) bool {
+	var v element
+	for i := range v {
+		v[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	var borrow uint64
+	for i := range v {
+		_, borrow = bits.Sub64(v[i], modulus[i], borrow)
+	}
+	z.mul(&v, &rSquare)
+	return borrow == 1
+}
+
+// bytes returns the canonical little-endian encoding of z.
+func (z *element) bytes() [32]byte {
+	var v element
+	v.mul(z, &element{1})
+	var b [32]byte
+	for i := range v {
+		binary.LittleEndian.PutUint64(b[8*i:], v[i])
+	}
+	return b
+}
@@ -0,0 +1,147 @@
+/*
+This package has no purpose except to perform registration of multihashes.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/poseidon"
+	)
+
+This package registers poseidon-bls12_381-a2-fc1, the arity-two Poseidon
+hash Filecoin uses over the BLS12-381 scalar field. Its input is a sequence of
+32-byte little-endian field elements, which are the leaves of a binary Merkle
+tree; the digest is the root. Two elements hash to NodeHash of the pair, as
+comm_c and comm_r_last do to Filecoin's replica commitment comm_r.
+
+Writing a 32-byte value which is not below the field modulus fails with
+ErrNotFieldElement. A trailing partial element is zero-extended, and the
+leaves are padded with zero elements to a power of two, at least two.
+
+Nothing encodes the length of the input or the height of the tree, so
+distinct inputs share a digest: "abc" and "abc\x00", or four elements and
+the two roots of their halves. The code is therefore registered with no
+strength, like identity, and VerifyAny prefers any cryptographic hash over
+it. Callers must check that the input has the expected number of elements.
+*/
+package poseidon
+
+import (
+	"hash"
+	"math/bits"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	multihash.Register(multihash.POSEIDON_BLS12_381_A2_FC1, New)
+	multihash.RegisterStrength(multihash.POSEIDON_BLS12_381_A2_FC1, multihash.Strength{})
+}
+
+// NodeSize is the size of an encoded field element, in bytes.
+const NodeSize = 32
+
+// maxLevels bounds the height of a tree: the input cannot hold more than
+// 2^64 bytes, so there are fewer than 2^59 leaves.
+const maxLevels = 64
+
+// zeroNodes holds the root of a tree over zero leaves, by level. It is
+// filled in by setup.
+var zeroNodes [maxLevels]element
+
+// New returns a hash.Hash computing the root of the tree over the field
+// elements written to it. Only the nodes on the right edge of the tree are
+// kept in memory.
+func New() hash.Hash {
+	return new(digest)
+}
+
+type digest struct {
+	buf    [NodeSize]byte
+	n      int
+	leaves uint64
+	// stack holds the roots of the complete subtrees written so far, largest
+	// first. There is one for every bit set in leaves.
+	stack []element
+}
+
+func (d *digest) Size() int      { return NodeSize }
+func (d *digest) BlockSize() int { return NodeSize }
+
+func (d *digest) Reset() {
+	d.n = 0
+	d.leaves = 0
+	d.stack = d.stack[:0]
+}
+
+// Write adds the field elements in p to the tree. It stops at the first
+// element which is not canonical, returning ErrNotFieldElement and the number
+// of bytes before it.
+func (d *digest) Write(p []byte) (int, error) {
+	setup()
+	written := 0
+	for len(p) > 0 {
+		k := copy(d.buf[d.n:], p)
+		if d.n+k == NodeSize {
+			var e element
+			if !e.setBytes(&d.buf) {
+				return written, ErrNotFieldElement
+			}
+			d.addLeaf(e)
+			d.n = 0
+		} else {
+			d.n += k
+		}
+		written += k
+		p = p[k:]
+	}
+	return written, nil
+}
+
+func (d *digest) addLeaf(node element) {
+	for level := 0; d.leaves>>level&1 == 1; level++ {
+		node = hash2(&d.stack[len(d.stack)-1], &node)
+		d.stack = d.stack[:len(d.stack)-1]
+	}
+	d.stack = append(d.stack, node)
+	d.leaves++
+}
+
+// Sum appends the root of the tree to b. It does not change the state of the
+// digest.
+func (d *digest) Sum(b []byte) []byte {
+	setup()
+	dup := *d
+	dup.stack = append([]element(nil), d.stack...)
+	if dup.n > 0 {
+		// Below 2^248, a zero-extended partial element is always canonical.
+		var e element
+		clear(dup.buf[dup.n:])
+		e.setBytes(&dup.buf)
+		dup.addLeaf(e)
+	}
+	for dup.leaves < 2 {
+		dup.addLeaf(element{})
+	}
+
+	height := bits.Len64(dup.leaves - 1)
+	var cur *element
+	for level := 0; level < height; level++ {
+		if dup.leaves>>level&1 == 1 {
+			left := dup.stack[len(dup.stack)-1]
+			dup.stack = dup.stack[:len(dup.stack)-1]
+			if cur == nil {
+				cur = &zeroNodes[level]
+			}
+			parent := hash2(&left, cur)
+			cur = &parent
+		} else if cur != nil {
+			parent := hash2(cur, &zeroNodes[level])
+			cur = &parent
+		}
+	}
+	if cur == nil {
+		cur = &dup.stack[0]
+	}
+	root := cur.bytes()
+	return append(b, root[:]...)
+}
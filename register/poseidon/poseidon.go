@@ -0,0 +1,168 @@
+package poseidon
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrNotFieldElement is returned for a 32-byte value which is not the
+// canonical little-endian encoding of a BLS12-381 scalar.
+var ErrNotFieldElement = errors.New("poseidon: not a canonical field element")
+
+// The instance is Poseidon over the BLS12-381 scalar field with x^5 as the
+// S-box, a width of three elements, eight full rounds and 55 partial ones,
+// as used by Filecoin (the neptune crate at 128-bit security).
+const (
+	width         = 3
+	fullRounds    = 8
+	partialRounds = 55
+)
+
+// domainTag fills the capacity element when hashing a Merkle tree node of
+// arity two: 2^arity - 1.
+const domainTag = 3
+
+var (
+	roundConstants [(fullRounds + partialRounds) * width]element
+	mds            [width][width]element
+
+	setupOnce sync.Once
+)
+
+// setup derives the round constants, the MDS matrix and the roots of zero
+// trees. It runs on first use rather than at init, so that importing the
+// package costs nothing.
+func setup() {
+	setupOnce.Do(func() {
+		g := newGrain()
+		for i := range roundConstants {
+			roundConstants[i] = g.element()
+		}
+
+		// The MDS matrix is the Cauchy matrix 1/(x_i + y_j), with x_i = i
+		// and y_j = width + j.
+		for i := range mds {
+			for j := range mds[i] {
+				var x element
+				x.setUint64(uint64(i + width + j))
+				mds[i][j].inverse(&x)
+			}
+		}
+
+		for i := 1; i < maxLevels; i++ {
+			zeroNodes[i] = hash2(&zeroNodes[i-1], &zeroNodes[i-1])
+		}
+	})
+}
+
+// grain is the self-shrinking Grain LFSR which derives the round constants
+// from the parameters of the instance.
+type grain struct {
+	state [80]byte
+	pos   int
+}
+
+func newGrain() *grain {
+	g := new(grain)
+	bits := g.state[:0]
+	appendBits := func(v uint64, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, byte(v>>i&1))
+		}
+	}
+	appendBits(1, 2)    // prime field
+	appendBits(1, 4)    // S-box x^alpha
+	appendBits(255, 12) // field size in bits
+	appendBits(width, 12)
+	appendBits(fullRounds, 10)
+	appendBits(partialRounds, 10)
+	appendBits(1<<30-1, 30)
+	for i := 0; i < 160; i++ {
+		g.next()
+	}
+	return g
+}
+
+// next clocks the LFSR once and returns the new bit.
+func (g *grain) next() byte {
+	s := &g.state
+	p := g.pos
+	b := s[p] ^ s[(p+13)%80] ^ s[(p+23)%80] ^ s[(p+38)%80] ^ s[(p+51)%80] ^ s[(p+62)%80]
+	s[p] = b
+	g.pos = (p + 1) % 80
+	return b
+}
+
+// bit returns the next output bit: of every pair of LFSR bits, the second is
+// kept when the first is set and both are dropped otherwise.
+func (g *grain) bit() byte {
+	for {
+		if g.next() == 1 {
+			return g.next()
+		}
+		g.next()
+	}
+}
+
+// element draws 255-bit big-endian integers until one is below the modulus.
+func (g *grain) element() element {
+	for {
+		var v [32]byte
+		for i := 0; i < 255; i++ {
+			k := 254 - i
+			v[k/8] |= g.bit() << (k % 8)
+		}
+		var e element
+		if e.setBytes(&v) {
+			return e
+		}
+	}
+}
+
+// permute applies the Poseidon permutation to state.
+func permute(state *[width]element) {
+	rc := roundConstants[:]
+	for r := 0; r < fullRounds+partialRounds; r++ {
+		full := r < fullRounds/2 || r >= fullRounds/2+partialRounds
+		for i := range state {
+			state[i].add(&state[i], &rc[i])
+			if full || i == 0 {
+				state[i].pow5(&state[i])
+			}
+		}
+		rc = rc[width:]
+
+		var next [width]element
+		for j := range next {
+			for i := range state {
+				var t element
+				t.mul(&state[i], &mds[i][j])
+				next[j].add(&next[j], &t)
+			}
+		}
+		*state = next
+	}
+}
+
+// hash2 returns the Poseidon hash of two field elements.
+func hash2(left, right *element) element {
+	var state [width]element
+	state[0].setUint64(domainTag)
+	state[1], state[2] = *left, *right
+	permute(&state)
+	return state[1]
+}
+
+// NodeHash returns the parent of two nodes of a Merkle tree, each the
+// canonical little-endian encoding of a field element. Filecoin derives the
+// replica commitment this way, as the hash of comm_c and comm_r_last.
+func NodeHash(left, right [NodeSize]byte) ([NodeSize]byte, error) {
+	setup()
+	var l, r element
+	okL, okR := l.setBytes(&left), r.setBytes(&right)
+	if !okL || !okR {
+		return [NodeSize]byte{}, ErrNotFieldElement
+	}
+	h := hash2(&l, &r)
+	return h.bytes(), nil
+}
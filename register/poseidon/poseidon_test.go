@@ -0,0 +1,207 @@
+package poseidon
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"math/rand"
+	"slices"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+// fromInt returns the little-endian encoding of an integer given in
+// big-endian hex.
+func fromInt(s string) [NodeSize]byte {
+	d, err := hex.DecodeString(s)
+	if err != nil || len(d) != NodeSize {
+		panic("bad test vector: " + s)
+	}
+	slices.Reverse(d)
+	return [NodeSize]byte(d)
+}
+
+func uintNode(v byte) [NodeSize]byte {
+	return [NodeSize]byte{v}
+}
+
+// elements returns the encodings of small field elements, end to end.
+func elements(vs ...byte) []byte {
+	var out []byte
+	for _, v := range vs {
+		n := uintNode(v)
+		out = append(out, n[:]...)
+	}
+	return out
+}
+
+// Hashes of two field elements, as integers. (0, 1) is the arity-two vector
+// of neptune's hash_values test, (0, 0) that of triplewz/poseidon's
+// TestPoseidonHashFixed; (1, 2) was computed with the latter.
+var nodeHashes = []struct {
+	left, right byte
+	hash        string
+}{
+	{0, 1, "396508d75e76a56b739e0fd902efe161a6fba9339d05a69d2e203c369a02e7ff"},
+	{0, 0, "48fe0b1331196f6cdb33a7c6e5af61b76fd388e1ef1d3d418be5147f0e4613d4"},
+	{1, 2, "6d6f8106657f1f4d7babcbaf436a9d7669c04e726e5896d89317d9833e5fa9be"},
+}
+
+func TestNodeHash(t *testing.T) {
+	for _, tc := range nodeHashes {
+		result, err := NodeHash(uintNode(tc.left), uintNode(tc.right))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := fromInt(tc.hash); result != expected {
+			t.Errorf("(%d, %d): expected %x; got %x", tc.left, tc.right, expected, result)
+		}
+	}
+}
+
+func TestRoundConstants(t *testing.T) {
+	setup()
+	// The first and last constants generated by neptune for this instance.
+	first, _ := new(big.Int).SetString("46416882697619310563126672610826606220566394200493645530692832366525156348888", 10)
+	last, _ := new(big.Int).SetString("43817363063905032294035947848440198558447209134068535812493400490274157321820", 10)
+	for _, tc := range []struct {
+		e        element
+		expected *big.Int
+	}{
+		{roundConstants[0], first},
+		{roundConstants[len(roundConstants)-1], last},
+	} {
+		if result := toBig(&tc.e); result.Cmp(tc.expected) != 0 {
+			t.Errorf("expected %s; got %s", tc.expected, result)
+		}
+	}
+}
+
+func toBig(e *element) *big.Int {
+	b := e.bytes()
+	slices.Reverse(b[:])
+	return new(big.Int).SetBytes(b[:])
+}
+
+// limbs returns the integer held in e, without leaving Montgomery form.
+func limbs(e element) *big.Int {
+	v := new(big.Int)
+	for i := len(e) - 1; i >= 0; i-- {
+		v.Lsh(v, 64).Or(v, new(big.Int).SetUint64(e[i]))
+	}
+	return v
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	r, _ := new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+	rng := rand.New(rand.NewSource(1))
+	random := func() (element, *big.Int) {
+		var b [NodeSize]byte
+		rng.Read(b[:])
+		b[NodeSize-1] &= 0x7f
+		var e element
+		for !e.setBytes(&b) {
+			b[NodeSize-1] >>= 1
+		}
+		return e, toBig(&e)
+	}
+	for i := 0; i < 1000; i++ {
+		x, bx := random()
+		y, by := random()
+		var sum, prod element
+		sum.add(&x, &y)
+		prod.mul(&x, &y)
+		if expected := new(big.Int).Add(bx, by); toBig(&sum).Cmp(expected.Mod(expected, r)) != 0 {
+			t.Fatalf("%s + %s: expected %s; got %s", bx, by, expected, toBig(&sum))
+		}
+		if expected := new(big.Int).Mul(bx, by); toBig(&prod).Cmp(expected.Mod(expected, r)) != 0 {
+			t.Fatalf("%s * %s: expected %s; got %s", bx, by, expected, toBig(&prod))
+		}
+	}
+
+	// The precomputed Montgomery constants.
+	if modulus[0]*qInv != ^uint64(0) {
+		t.Error("qInv is not -r^-1 mod 2^64")
+	}
+	R := new(big.Int).Lsh(big.NewInt(1), 256)
+	if raw := limbs(rOne); raw.Cmp(new(big.Int).Mod(R, r)) != 0 {
+		t.Errorf("rOne: got %x", raw)
+	}
+	if raw := limbs(rSquare); raw.Cmp(new(big.Int).Mod(new(big.Int).Mul(R, R), r)) != 0 {
+		t.Errorf("rSquare: got %x", raw)
+	}
+
+	// r - 1 and r are the largest canonical encoding and the smallest
+	// non-canonical one.
+	var e element
+	if b := fromInt("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000000"); !e.setBytes(&b) || e.bytes() != b {
+		t.Error("r - 1 rejected")
+	}
+	if b := fromInt("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001"); e.setBytes(&b) {
+		t.Error("r accepted")
+	}
+}
+
+func TestHasher(t *testing.T) {
+	n01, _ := NodeHash(uintNode(0), uintNode(1))
+	n00, _ := NodeHash(uintNode(0), uintNode(0))
+	n0100, _ := NodeHash(n01, n00)
+	n23, _ := NodeHash(uintNode(2), uintNode(3))
+	n0123, _ := NodeHash(n01, n23)
+
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		expected [NodeSize]byte
+	}{
+		{"empty", nil, n00},
+		{"one element", elements(0), n00},
+		{"two elements", elements(0, 1), n01},
+		{"partial element", append(elements(0), 1), n01},
+		{"three elements", elements(0, 1, 0), n0100},
+		{"four elements", elements(0, 1, 2, 3), n0123},
+	} {
+		h, err := multihash.GetHasher(multihash.POSEIDON_BLS12_381_A2_FC1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := h.Write(tc.data); err != nil {
+			t.Fatalf("%s: %s", tc.name, err)
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected[:]) {
+			t.Errorf("%s: expected %x; got %x", tc.name, tc.expected, result)
+		}
+	}
+}
+
+func TestHasherStreaming(t *testing.T) {
+	data := make([]byte, 37*NodeSize+5)
+	for i := 0; i < len(data); i += NodeSize {
+		data[i] = byte(i / NodeSize)
+	}
+	h := New()
+	h.Write(data)
+	expected := h.Sum(nil)
+	h.Reset()
+	for p := data; len(p) > 0; {
+		k := min(len(p), 7)
+		h.Write(p[:k])
+		p = p[k:]
+	}
+	if result := h.Sum(nil); !bytes.Equal(result, expected) {
+		t.Errorf("expected %x; got %x", expected, result)
+	}
+}
+
+func TestNonCanonical(t *testing.T) {
+	r := fromInt("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001")
+	if _, err := NodeHash(uintNode(0), r); !errors.Is(err, ErrNotFieldElement) {
+		t.Errorf("NodeHash: expected ErrNotFieldElement; got %v", err)
+	}
+	n, err := New().Write(append(make([]byte, NodeSize), r[:]...))
+	if !errors.Is(err, ErrNotFieldElement) || n != NodeSize {
+		t.Errorf("Write: expected %d, ErrNotFieldElement; got %d, %v", NodeSize, n, err)
+	}
+}
@@ -0,0 +1,33 @@
+/*
+This package has no purpose except to perform registration of multihashes.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/ripemd"
+	)
+
+This package registers the RIPEMD family: ripemd-128, ripemd-160,
+ripemd-256 and ripemd-320. ripemd-160 comes from golang.org/x/crypto; the
+others are implemented here.
+*/
+package ripemd
+
+import (
+	"golang.org/x/crypto/ripemd160"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	multihash.Register(multihash.RIPEMD_128, New128)
+	multihash.Register(multihash.RIPEMD_160, ripemd160.New)
+	multihash.Register(multihash.RIPEMD_256, New256)
+	multihash.Register(multihash.RIPEMD_320, New320)
+
+	// The double-width variants are no stronger than the single-width ones.
+	multihash.RegisterStrength(multihash.RIPEMD_128, multihash.Strength{Bits: 64, Cryptographic: true})
+	multihash.RegisterStrength(multihash.RIPEMD_160, multihash.Strength{Bits: 80, Cryptographic: true})
+	multihash.RegisterStrength(multihash.RIPEMD_256, multihash.Strength{Bits: 64, Cryptographic: true})
+	multihash.RegisterStrength(multihash.RIPEMD_320, multihash.Strength{Bits: 80, Cryptographic: true})
+}
@@ -0,0 +1,219 @@
+package ripemd
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// Digest sizes, in bytes.
+const (
+	Size128 = 16
+	Size160 = 20
+	Size256 = 32
+	Size320 = 40
+)
+
+const blockSize = 64
+
+var (
+	iv128 = []uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+	iv256 = []uint32{
+		0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476,
+		0x76543210, 0xfedcba98, 0x89abcdef, 0x01234567,
+	}
+	iv320 = []uint32{
+		0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0,
+		0x76543210, 0xfedcba98, 0x89abcdef, 0x01234567, 0x3c2d1e0f,
+	}
+)
+
+// Message word order, rotations and constants of the left and right lines,
+// by round.
+var (
+	rLeft = [5][16]uint8{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		{7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8},
+		{3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12},
+		{1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2},
+		{4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13},
+	}
+	rRight = [5][16]uint8{
+		{5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12},
+		{6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2},
+		{15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13},
+		{8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14},
+		{12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11},
+	}
+	sLeft = [5][16]uint8{
+		{11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8},
+		{7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12},
+		{11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5},
+		{11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12},
+		{9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6},
+	}
+	sRight = [5][16]uint8{
+		{8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6},
+		{9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11},
+		{9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5},
+		{15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8},
+		{8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11},
+	}
+	kLeft  = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	kRight = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+	// The 4-round variants use these on the right line instead.
+	kRight4 = [4]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x00000000}
+)
+
+// f applies the boolean function of round j.
+func f(j int, x, y, z uint32) uint32 {
+	switch j {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return (x & y) | (^x & z)
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return (x & z) | (y &^ z)
+	default:
+		return x ^ (y | ^z)
+	}
+}
+
+// round4 runs one round of a 4-word line over x.
+func round4(v *[4]uint32, x *[16]uint32, round, fn int, r, s *[5][16]uint8, k uint32) {
+	a, b, c, d := v[0], v[1], v[2], v[3]
+	for i := 0; i < 16; i++ {
+		t := bits.RotateLeft32(a+f(fn, b, c, d)+x[r[round][i]]+k, int(s[round][i]))
+		a, b, c, d = d, t, b, c
+	}
+	*v = [4]uint32{a, b, c, d}
+}
+
+// round5 runs one round of a 5-word line over x.
+func round5(v *[5]uint32, x *[16]uint32, round, fn int, r, s *[5][16]uint8, k uint32) {
+	a, b, c, d, e := v[0], v[1], v[2], v[3], v[4]
+	for i := 0; i < 16; i++ {
+		t := bits.RotateLeft32(a+f(fn, b, c, d)+x[r[round][i]]+k, int(s[round][i])) + e
+		a, b, c, d, e = e, t, b, bits.RotateLeft32(c, 10), d
+	}
+	*v = [5]uint32{a, b, c, d, e}
+}
+
+func block128(h []uint32, x *[16]uint32) {
+	l := [4]uint32(h[:4])
+	r := l
+	for j := 0; j < 4; j++ {
+		round4(&l, x, j, j, &rLeft, &sLeft, kLeft[j])
+		round4(&r, x, j, 3-j, &rRight, &sRight, kRight4[j])
+	}
+	h[0], h[1], h[2], h[3] = h[1]+l[2]+r[3], h[2]+l[3]+r[0], h[3]+l[0]+r[1], h[0]+l[1]+r[2]
+}
+
+func block256(h []uint32, x *[16]uint32) {
+	l := [4]uint32(h[:4])
+	r := [4]uint32(h[4:8])
+	for j := 0; j < 4; j++ {
+		round4(&l, x, j, j, &rLeft, &sLeft, kLeft[j])
+		round4(&r, x, j, 3-j, &rRight, &sRight, kRight4[j])
+		// After round j, the lines swap their word j.
+		l[j], r[j] = r[j], l[j]
+	}
+	for i := 0; i < 4; i++ {
+		h[i] += l[i]
+		h[4+i] += r[i]
+	}
+}
+
+// swap320 lists the word the lines swap after each round of RIPEMD-320.
+var swap320 = [5]int{1, 3, 0, 2, 4}
+
+func block320(h []uint32, x *[16]uint32) {
+	l := [5]uint32(h[:5])
+	r := [5]uint32(h[5:10])
+	for j := 0; j < 5; j++ {
+		round5(&l, x, j, j, &rLeft, &sLeft, kLeft[j])
+		round5(&r, x, j, 4-j, &rRight, &sRight, kRight[j])
+		w := swap320[j]
+		l[w], r[w] = r[w], l[w]
+	}
+	for i := 0; i < 5; i++ {
+		h[i] += l[i]
+		h[5+i] += r[i]
+	}
+}
+
+// digest is the Merkle-Damgård construction shared by the RIPEMD variants.
+type digest struct {
+	h     [10]uint32
+	iv    []uint32
+	block func(h []uint32, x *[16]uint32)
+	buf   [blockSize]byte
+	n     int
+	len   uint64
+}
+
+// New128 returns RIPEMD-128.
+func New128() hash.Hash { return newDigest(iv128, block128) }
+
+// New256 returns RIPEMD-256. It is no stronger than RIPEMD-128; it only has
+// a longer digest.
+func New256() hash.Hash { return newDigest(iv256, block256) }
+
+// New320 returns RIPEMD-320. It is no stronger than RIPEMD-160; it only has
+// a longer digest.
+func New320() hash.Hash { return newDigest(iv320, block320) }
+
+func newDigest(iv []uint32, block func([]uint32, *[16]uint32)) *digest {
+	d := &digest{iv: iv, block: block}
+	d.Reset()
+	return d
+}
+
+func (d *digest) Size() int { return 4 * len(d.iv) }
+
+func (d *digest) BlockSize() int { return blockSize }
+
+func (d *digest) Reset() {
+	copy(d.h[:], d.iv)
+	d.n = 0
+	d.len = 0
+}
+
+func (d *digest) compress(p []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(p[4*i:])
+	}
+	d.block(d.h[:len(d.iv)], &x)
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	d.len += uint64(written)
+	for len(p) > 0 {
+		k := copy(d.buf[d.n:], p)
+		d.n += k
+		p = p[k:]
+		if d.n == blockSize {
+			d.compress(d.buf[:])
+			d.n = 0
+		}
+	}
+	return written, nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	dup := *d
+	var pad [blockSize + 8]byte
+	pad[0] = 0x80
+	padLen := (blockSize + 56 - dup.n - 1) % blockSize
+	binary.LittleEndian.PutUint64(pad[1+padLen:], d.len*8)
+	dup.Write(pad[:1+padLen+8])
+
+	for _, w := range dup.h[:len(d.iv)] {
+		b = binary.LittleEndian.AppendUint32(b, w)
+	}
+	return b
+}
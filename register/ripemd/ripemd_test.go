@@ -0,0 +1,57 @@
+package ripemd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestRIPEMD(t *testing.T) {
+	digits := strings.Repeat("1234567890", 8)
+
+	// From the RIPEMD page of the designers, and ISO/IEC 10118-3.
+	for _, tc := range []struct {
+		code     uint64
+		data     string
+		expected []byte
+	}{
+		{multihash.RIPEMD_128, "", mustHexDecode("cdf26213a150dc3ecb610f18f6b38b46")},
+		{multihash.RIPEMD_128, "abc", mustHexDecode("c14a12199c66e4ba84636b0f69144c77")},
+		{multihash.RIPEMD_128, "message digest", mustHexDecode("9e327b3d6e523062afc1132d7df9d1b8")},
+		{multihash.RIPEMD_128, digits, mustHexDecode("3f45ef194732c2dbb2c4a2c769795fa3")},
+		{multihash.RIPEMD_160, "", mustHexDecode("9c1185a5c5e9fc54612808977ee8f548b2258d31")},
+		{multihash.RIPEMD_160, "abc", mustHexDecode("8eb208f7e05d987a9b044a8e98c6b087f15a0bfc")},
+		{multihash.RIPEMD_160, digits, mustHexDecode("9b752e45573d4b39f4dbd3323cab82bf63326bfb")},
+		{multihash.RIPEMD_256, "", mustHexDecode("02ba4c4e5f8ecd1877fc52d64d30e37a2d9774fb1e5d026380ae0168e3c5522d")},
+		{multihash.RIPEMD_256, "abc", mustHexDecode("afbd6e228b9d8cbbcef5ca2d03e6dba10ac0bc7dcbe4680e1e42d2e975459b65")},
+		{multihash.RIPEMD_256, "message digest", mustHexDecode("87e971759a1ce47a514d5c914c392c9018c7c46bc14465554afcdf54a5070c0e")},
+		{multihash.RIPEMD_256, digits, mustHexDecode("06fdcc7a409548aaf91368c06a6275b553e3f099bf0ea4edfd6778df89a890dd")},
+		{multihash.RIPEMD_320, "", mustHexDecode("22d65d5661536cdc75c1fdf5c6de7b41b9f27325ebc61e8557177d705a0ec880151c3a32a00899b8")},
+		{multihash.RIPEMD_320, "abc", mustHexDecode("de4c01b3054f8930a79d09ae738e92301e5a17085beffdc1b8d116713e74f82fa942d64cdbc4682d")},
+		{multihash.RIPEMD_320, "message digest", mustHexDecode("3a8e28502ed45d422f68844f9dd316e7b98533fa3f2a91d29f84d425c88d6b4eff727df66a7c0197")},
+		{multihash.RIPEMD_320, digits, mustHexDecode("557888af5f6d8ed62ab66945c6d2a0a47ecd5341e915eb8fea1d0524955f825dc717e4a008ab2d42")},
+	} {
+		h, err := multihash.GetHasher(tc.code)
+		if err != nil {
+			t.Fatalf("0x%x: failed to get: %s", tc.code, err)
+		}
+		// Write in uneven pieces to exercise the block buffering.
+		for i := 0; i < len(tc.data); i += 7 {
+			h.Write([]byte(tc.data[i:min(i+7, len(tc.data))]))
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("0x%x: %q: expected %x; got %x", tc.code, tc.data, tc.expected, result)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"golang.org/x/crypto/sha3"
 
 	multihash "github.com/multiformats/go-multihash/core"
+	"github.com/multiformats/go-multihash/internal/keccak"
 )
 
 func init() {
@@ -30,6 +31,20 @@ func init() {
 	multihash.Register(multihash.SHAKE_256, func() hash.Hash { return shakeNormalizer{sha3.NewShake256(), 256 / 8 * 2} })
 	multihash.Register(multihash.KECCAK_256, sha3.NewLegacyKeccak256)
 	multihash.Register(multihash.KECCAK_512, sha3.NewLegacyKeccak512)
+	// x/crypto has no legacy Keccak at these sizes.
+	multihash.Register(multihash.KECCAK_224, func() hash.Hash { return keccak.NewLegacyKeccak(224 / 8) })
+	multihash.Register(multihash.KECCAK_384, func() hash.Hash { return keccak.NewLegacyKeccak(384 / 8) })
+
+	multihash.RegisterStrength(multihash.SHA3_512, multihash.Strength{Bits: 256, Cryptographic: true})
+	multihash.RegisterStrength(multihash.SHA3_384, multihash.Strength{Bits: 192, Cryptographic: true})
+	multihash.RegisterStrength(multihash.SHA3_256, multihash.Strength{Bits: 128, Cryptographic: true})
+	multihash.RegisterStrength(multihash.SHA3_224, multihash.Strength{Bits: 112, Cryptographic: true})
+	multihash.RegisterStrength(multihash.SHAKE_128, multihash.Strength{Bits: 128, Cryptographic: true})
+	multihash.RegisterStrength(multihash.SHAKE_256, multihash.Strength{Bits: 256, Cryptographic: true})
+	multihash.RegisterStrength(multihash.KECCAK_256, multihash.Strength{Bits: 128, Cryptographic: true})
+	multihash.RegisterStrength(multihash.KECCAK_512, multihash.Strength{Bits: 256, Cryptographic: true})
+	multihash.RegisterStrength(multihash.KECCAK_224, multihash.Strength{Bits: 112, Cryptographic: true})
+	multihash.RegisterStrength(multihash.KECCAK_384, multihash.Strength{Bits: 192, Cryptographic: true})
 }
 
 // sha3.ShakeHash presents a somewhat odd interface, and requires a wrapper to normalize it to the usual hash.Hash interface.
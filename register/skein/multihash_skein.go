@@ -0,0 +1,58 @@
+/*
+This package has no purpose except to perform registration of multihashes.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/skein"
+	)
+
+This package registers the skein family: Skein-256, Skein-512 and
+Skein-1024, at every output size from 8 bits to the state size.
+*/
+package skein
+
+import (
+	"hash"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+const (
+	skein256_min  = 0xb301
+	skein256_max  = 0xb320
+	skein512_min  = 0xb321
+	skein512_max  = 0xb360
+	skein1024_min = 0xb361
+	skein1024_max = 0xb3e0
+)
+
+func init() {
+	for _, family := range []struct {
+		min, max  uint64
+		stateSize int
+	}{
+		{skein256_min, skein256_max, Size256},
+		{skein512_min, skein512_max, Size512},
+		{skein1024_min, skein1024_max, Size1024},
+	} {
+		for c := family.min; c <= family.max; c++ {
+			size := int(c - family.min + 1)
+			stateSize := family.stateSize
+
+			// Like blake2, the output size is part of the configuration, so
+			// each size is a distinct hash rather than a truncation.
+			multihash.RegisterVariableSize(c, func(sizeHint int) (hash.Hash, bool) {
+				if sizeHint > size {
+					return nil, false
+				}
+				hasher, err := New(stateSize, size)
+				if err != nil {
+					panic(err)
+				}
+				return hasher, true
+			})
+			multihash.RegisterStrength(c, multihash.Strength{Bits: size * 8 / 2, Cryptographic: true})
+		}
+	}
+}
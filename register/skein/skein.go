@@ -0,0 +1,241 @@
+package skein
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+// State sizes of the Skein variants, in bytes.
+const (
+	Size256  = 32
+	Size512  = 64
+	Size1024 = 128
+)
+
+// UBI block types.
+const (
+	typeCfg = 4
+	typeMsg = 48
+	typeOut = 63
+)
+
+const keyScheduleParity = 0x1BD11BDAA9FC1A22
+
+// threefish holds the parameters of one Threefish block size.
+type threefish struct {
+	words     int
+	rounds    int
+	rotations [8][]int
+	perm      []int
+}
+
+var (
+	threefish256 = &threefish{
+		words:  4,
+		rounds: 72,
+		rotations: [8][]int{
+			{14, 16}, {52, 57}, {23, 40}, {5, 37},
+			{25, 33}, {46, 12}, {58, 22}, {32, 32},
+		},
+		perm: []int{0, 3, 2, 1},
+	}
+	threefish512 = &threefish{
+		words:  8,
+		rounds: 72,
+		rotations: [8][]int{
+			{46, 36, 19, 37}, {33, 27, 14, 42}, {17, 49, 36, 39}, {44, 9, 54, 56},
+			{39, 30, 34, 24}, {13, 50, 10, 17}, {25, 29, 39, 43}, {8, 35, 56, 22},
+		},
+		perm: []int{2, 1, 4, 7, 6, 5, 0, 3},
+	}
+	threefish1024 = &threefish{
+		words:  16,
+		rounds: 80,
+		rotations: [8][]int{
+			{24, 13, 8, 47, 8, 17, 22, 37}, {38, 19, 10, 55, 49, 18, 23, 52},
+			{33, 4, 51, 13, 34, 41, 59, 17}, {5, 20, 48, 41, 47, 28, 16, 25},
+			{41, 9, 37, 31, 12, 47, 44, 30}, {16, 34, 56, 51, 4, 53, 42, 41},
+			{31, 44, 47, 46, 19, 42, 44, 25}, {9, 48, 35, 52, 23, 31, 37, 20},
+		},
+		perm: []int{0, 9, 2, 13, 6, 11, 4, 15, 10, 7, 12, 3, 14, 5, 8, 1},
+	}
+)
+
+// encrypt enciphers block in place with the key and tweak.
+func (tf *threefish) encrypt(key, block []uint64, tweak [2]uint64) {
+	nw := tf.words
+	var k [17]uint64
+	k[nw] = keyScheduleParity
+	for i := 0; i < nw; i++ {
+		k[i] = key[i]
+		k[nw] ^= key[i]
+	}
+	t := [3]uint64{tweak[0], tweak[1], tweak[0] ^ tweak[1]}
+
+	addSubkey := func(s int) {
+		for i := 0; i < nw; i++ {
+			block[i] += k[(s+i)%(nw+1)]
+		}
+		block[nw-3] += t[s%3]
+		block[nw-2] += t[(s+1)%3]
+		block[nw-1] += uint64(s)
+	}
+
+	var f [16]uint64
+	for d := 0; d < tf.rounds; d++ {
+		if d%4 == 0 {
+			addSubkey(d / 4)
+		}
+		r := tf.rotations[d%8]
+		for j := 0; j < nw/2; j++ {
+			x0, x1 := block[2*j], block[2*j+1]
+			x0 += x1
+			f[2*j] = x0
+			f[2*j+1] = bits.RotateLeft64(x1, r[j]) ^ x0
+		}
+		for i := 0; i < nw; i++ {
+			block[i] = f[tf.perm[i]]
+		}
+	}
+	addSubkey(tf.rounds / 4)
+}
+
+// digest is Skein with a given state and output size, without a key or tree
+// hashing.
+type digest struct {
+	tf   *threefish
+	size int
+
+	// iv is the chaining value after the configuration block.
+	iv  [16]uint64
+	h   [16]uint64
+	buf [Size1024]byte
+	n   int
+	// pos is the number of message bytes processed, including buf.
+	pos   uint64
+	first bool
+}
+
+// New returns Skein with an internal state of stateSize bytes (Size256,
+// Size512 or Size1024) and a digest of size bytes, at most 255 times the
+// state size.
+func New(stateSize, size int) (hash.Hash, error) {
+	var tf *threefish
+	switch stateSize {
+	case Size256:
+		tf = threefish256
+	case Size512:
+		tf = threefish512
+	case Size1024:
+		tf = threefish1024
+	default:
+		return nil, errors.New("skein: invalid state size")
+	}
+	if size < 1 || size > 255*stateSize {
+		return nil, errors.New("skein: invalid digest size")
+	}
+	d := &digest{tf: tf, size: size}
+
+	var cfg [32]byte
+	copy(cfg[:], "SHA3")
+	binary.LittleEndian.PutUint16(cfg[4:], 1)
+	binary.LittleEndian.PutUint64(cfg[8:], uint64(size)*8)
+	d.ubi(d.iv[:tf.words], cfg[:], typeCfg)
+
+	d.Reset()
+	return d, nil
+}
+
+// ubi runs one UBI invocation over msg, updating the chaining value h.
+func (d *digest) ubi(h []uint64, msg []byte, typ uint64) {
+	bs := d.tf.words * 8
+	var block [Size1024]byte
+	var pos uint64
+	first := true
+	for {
+		n := copy(block[:bs], msg)
+		clear(block[n:bs])
+		msg = msg[n:]
+		pos += uint64(n)
+		d.compress(h, block[:bs], pos, typ, first, len(msg) == 0)
+		first = false
+		if len(msg) == 0 {
+			return
+		}
+	}
+}
+
+// compress processes one block into the chaining value h.
+func (d *digest) compress(h []uint64, block []byte, pos, typ uint64, first, final bool) {
+	nw := d.tf.words
+	var m, x [16]uint64
+	for i := 0; i < nw; i++ {
+		m[i] = binary.LittleEndian.Uint64(block[8*i:])
+	}
+	x = m
+	tweak := [2]uint64{pos, typ << 56}
+	if first {
+		tweak[1] |= 1 << 62
+	}
+	if final {
+		tweak[1] |= 1 << 63
+	}
+	d.tf.encrypt(h, x[:nw], tweak)
+	for i := 0; i < nw; i++ {
+		h[i] = x[i] ^ m[i]
+	}
+}
+
+func (d *digest) Size() int { return d.size }
+
+func (d *digest) BlockSize() int { return d.tf.words * 8 }
+
+func (d *digest) Reset() {
+	d.h = d.iv
+	d.n = 0
+	d.pos = 0
+	d.first = true
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	bs := d.tf.words * 8
+	for len(p) > 0 {
+		// The last block is flagged as final, so a full buffer is only
+		// compressed once more data follows.
+		if d.n == bs {
+			d.compress(d.h[:d.tf.words], d.buf[:bs], d.pos, typeMsg, d.first, false)
+			d.first = false
+			d.n = 0
+		}
+		k := copy(d.buf[d.n:bs], p)
+		d.n += k
+		d.pos += uint64(k)
+		p = p[k:]
+	}
+	return written, nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	nw := d.tf.words
+	bs := nw * 8
+
+	h := d.h
+	block := d.buf
+	clear(block[d.n:bs])
+	d.compress(h[:nw], block[:bs], d.pos, typeMsg, d.first, true)
+
+	out := make([]byte, 0, d.size+bs)
+	var counter [8]byte
+	for i := uint64(0); len(out) < d.size; i++ {
+		o := h
+		binary.LittleEndian.PutUint64(counter[:], i)
+		d.ubi(o[:nw], counter[:], typeOut)
+		for _, w := range o[:nw] {
+			out = binary.LittleEndian.AppendUint64(out, w)
+		}
+	}
+	return append(b, out[:d.size]...)
+}
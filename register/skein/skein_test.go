@@ -0,0 +1,112 @@
+package skein
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// descending returns n bytes counting down from 0xff, the messages of the
+// Skein 1.3 reference vectors.
+func descending(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(0xff - i)
+	}
+	return b
+}
+
+func TestSkein(t *testing.T) {
+	for _, tc := range []struct {
+		code     uint64
+		data     []byte
+		expected []byte
+	}{
+		// Skein 1.3, appendix C.
+		{skein256_max, descending(1), mustHexDecode("0b98dcd198ea0e50a7a244c444e25c23da30c10fc9a1f270a6637f1f34e67ed2")},
+		{skein256_max, descending(32), mustHexDecode("8d0fa4ef777fd759dfd4044e6f6a5ac3c774aec943dcfc07927b723b5dbf408b")},
+		{skein512_max, descending(1), mustHexDecode("71b7bce6fe6452227b9ced6014249e5bf9a9754c3ad618ccc4e0aae16b316cc8ca698d864307ed3e80b6ef1570812ac5272dc409b5a012df2a579102f340617a")},
+		{skein1024_max, descending(1), mustHexDecode("e62c05802ea0152407cdd8787fda9e35703de862a4fbc119cff8590afe79250bccc8b3faf1bd2422ab5c0d263fb2f8afb3f796f048000381531b6f00d85161bc0fff4bef2486b1ebcd3773fabf50ad4ad5639af9040e3f29c6c931301bf79832e9da09857e831e82ef8b4691c235656515d437d2bda33bcec001c67ffde15ba8")},
+		// Empty messages.
+		{skein256_max, nil, mustHexDecode("c8877087da56e072870daa843f176e9453115929094c3a40c463a196c29bf7ba")},
+		{skein512_min + 31, nil, mustHexDecode("39ccc4554a8b31853b9de7a1fe638a24cce6b35a55f2431009e18780335d2621")},
+		{skein512_max, nil, mustHexDecode("bc5b4c50925519c290cc634277ae3d6257212395cba733bbad37a4af0fa06af41fca7903d06564fea7a2d3730dbdb80c1f85562dfcc070334ea4d1d9e72cba7a")},
+	} {
+		h, err := multihash.GetHasher(tc.code)
+		if err != nil {
+			t.Fatalf("0x%x: failed to get: %s", tc.code, err)
+		}
+		if h.Size() != len(tc.expected) {
+			t.Errorf("0x%x: expected size %d; got %d", tc.code, len(tc.expected), h.Size())
+		}
+		h.Write(tc.data)
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("0x%x: %d bytes: expected %x; got %x", tc.code, len(tc.data), tc.expected, result)
+		}
+	}
+}
+
+func TestSkeinSizes(t *testing.T) {
+	data := descending(200)
+	for _, family := range []struct {
+		min, max uint64
+	}{
+		{skein256_min, skein256_max},
+		{skein512_min, skein512_max},
+		{skein1024_min, skein1024_max},
+	} {
+		var prev []byte
+		for c := family.min; c <= family.max; c++ {
+			size := int(c - family.min + 1)
+			t.Run(fmt.Sprintf("0x%x", c), func(t *testing.T) {
+				h, err := multihash.GetHasher(c)
+				if err != nil {
+					t.Fatalf("failed to get: %s", err)
+				}
+				// Write in uneven pieces, across block boundaries.
+				for i := 0; i < len(data); i += 13 {
+					h.Write(data[i:min(i+13, len(data))])
+				}
+				result := h.Sum(nil)
+				if len(result) != size {
+					t.Fatalf("expected %d bytes; got %d", size, len(result))
+				}
+
+				h2, err := multihash.GetHasher(c)
+				if err != nil {
+					t.Fatal(err)
+				}
+				h2.Write(data)
+				if !bytes.Equal(result, h2.Sum(nil)) {
+					t.Error("digest depends on how the data was written")
+				}
+
+				// Sizes are distinct hashes, not truncations of each other.
+				if prev != nil && bytes.HasPrefix(result, prev) {
+					t.Error("digest extends the next smaller size")
+				}
+				prev = result
+			})
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New(48, 32); err == nil {
+		t.Error("expected an error for an invalid state size")
+	}
+	if _, err := New(Size256, 0); err == nil {
+		t.Error("expected an error for a zero digest size")
+	}
+}
@@ -0,0 +1,138 @@
+/*
+This package has no purpose except to register the sm3-256 hash function.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/sm3"
+	)
+
+SM3 is the hash function of the Chinese national standard GB/T 32905-2016.
+*/
+package sm3
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	multihash.Register(multihash.SM3_256, New)
+	multihash.RegisterStrength(multihash.SM3_256, multihash.Strength{Bits: 128, Cryptographic: true})
+}
+
+// Size and BlockSize of SM3, in bytes.
+const (
+	Size      = 32
+	BlockSize = 64
+)
+
+var iv = [8]uint32{
+	0x7380166f, 0x4914b2b9, 0x172442d7, 0xda8a0600,
+	0xa96f30bc, 0x163138aa, 0xe38dee4d, 0xb0fb0e4e,
+}
+
+type digest struct {
+	h   [8]uint32
+	buf [BlockSize]byte
+	n   int
+	len uint64
+}
+
+// New returns SM3.
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) BlockSize() int { return BlockSize }
+
+func (d *digest) Reset() {
+	d.h = iv
+	d.n = 0
+	d.len = 0
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	written := len(p)
+	d.len += uint64(written)
+	for len(p) > 0 {
+		k := copy(d.buf[d.n:], p)
+		d.n += k
+		p = p[k:]
+		if d.n == BlockSize {
+			d.compress()
+			d.n = 0
+		}
+	}
+	return written, nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	dup := *d
+	var pad [BlockSize + 8]byte
+	pad[0] = 0x80
+	padLen := (BlockSize + 56 - dup.n - 1) % BlockSize
+	binary.BigEndian.PutUint64(pad[1+padLen:], d.len*8)
+	dup.Write(pad[:1+padLen+8])
+
+	for _, w := range dup.h {
+		b = binary.BigEndian.AppendUint32(b, w)
+	}
+	return b
+}
+
+func p0(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 9) ^ bits.RotateLeft32(x, 17) }
+
+func p1(x uint32) uint32 { return x ^ bits.RotateLeft32(x, 15) ^ bits.RotateLeft32(x, 23) }
+
+func (d *digest) compress() {
+	var w [68]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(d.buf[4*i:])
+	}
+	for j := 16; j < 68; j++ {
+		w[j] = p1(w[j-16]^w[j-9]^bits.RotateLeft32(w[j-3], 15)) ^ bits.RotateLeft32(w[j-13], 7) ^ w[j-6]
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for j := 0; j < 64; j++ {
+		var t, ff, gg uint32
+		if j < 16 {
+			t = 0x79cc4519
+			ff = a ^ b ^ c
+			gg = e ^ f ^ g
+		} else {
+			t = 0x7a879d8a
+			ff = (a & b) | (a & c) | (b & c)
+			gg = (e & f) | (^e & g)
+		}
+		a12 := bits.RotateLeft32(a, 12)
+		ss1 := bits.RotateLeft32(a12+e+bits.RotateLeft32(t, j%32), 7)
+		ss2 := ss1 ^ a12
+		tt1 := ff + dd + ss2 + (w[j] ^ w[j+4])
+		tt2 := gg + h + ss1 + w[j]
+		dd = c
+		c = bits.RotateLeft32(b, 9)
+		b = a
+		a = tt1
+		h = g
+		g = bits.RotateLeft32(f, 19)
+		f = e
+		e = p0(tt2)
+	}
+	d.h[0] ^= a
+	d.h[1] ^= b
+	d.h[2] ^= c
+	d.h[3] ^= dd
+	d.h[4] ^= e
+	d.h[5] ^= f
+	d.h[6] ^= g
+	d.h[7] ^= h
+}
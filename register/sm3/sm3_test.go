@@ -0,0 +1,47 @@
+package sm3
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestSM3(t *testing.T) {
+	long := make([]byte, 200)
+	for i := range long {
+		long[i] = byte(i)
+	}
+
+	for _, tc := range []struct {
+		data     []byte
+		expected []byte
+	}{
+		// GB/T 32905-2016, appendix A.
+		{[]byte("abc"), mustHexDecode("66c7f0f462eeedd9d1f2d46bdc10e4e24167c4875cf2f7a2297da02b8f4ba8e0")},
+		{bytes.Repeat([]byte("abcd"), 16), mustHexDecode("debe9ff92275b8a138604889c18e5a4d6fdb70e5387e5765293dcba39c0c5732")},
+		{nil, mustHexDecode("1ab21d8355cfa17f8e61194831e81a8f22bec8c728fefb747ed035eb5082aa2b")},
+		{long, mustHexDecode("137c8be9a568df1f999ea75e042359e582990c708027d61f20489a368bf5ced5")},
+	} {
+		h, err := multihash.GetHasher(multihash.SM3_256)
+		if err != nil {
+			t.Fatalf("failed to get: %s", err)
+		}
+		// Write in uneven pieces to exercise the block buffering.
+		for i := 0; i < len(tc.data); i += 17 {
+			h.Write(tc.data[i:min(i+17, len(tc.data))])
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("%d bytes: expected %x; got %x", len(tc.data), tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package x11
+
+import "encoding/binary"
+
+// Groestl, SHAvite-3 and ECHO are built from the AES S-box and, for the
+// latter two, the AES round.
+
+var (
+	sbox [256]byte
+	// aesT holds the AES round tables: aesT[i][x] is column i of MixColumns
+	// applied to a column holding sbox[x] at row i, as a little-endian word.
+	aesT [4][256]uint32
+)
+
+func aesSetup() {
+	// The S-box is the inverse in GF(2^8), followed by an affine transform.
+	for x := 0; x < 256; x++ {
+		inv := byte(0)
+		if x != 0 {
+			inv = gfPow(byte(x), 254)
+		}
+		s := inv ^ rotl8(inv, 1) ^ rotl8(inv, 2) ^ rotl8(inv, 3) ^ rotl8(inv, 4) ^ 0x63
+		sbox[x] = s
+		col := [4]byte{gfMul(s, 2), s, s, gfMul(s, 3)}
+		for i := range aesT {
+			aesT[i][x] = uint32(col[(4-i)%4]) | uint32(col[(5-i)%4])<<8 | uint32(col[(6-i)%4])<<16 | uint32(col[(7-i)%4])<<24
+		}
+	}
+}
+
+func rotl8(x byte, n int) byte {
+	return x<<n | x>>(8-n)
+}
+
+// gfMul multiplies in GF(2^8) modulo the AES polynomial.
+func gfMul(a, b byte) byte {
+	var p byte
+	for ; b != 0; b >>= 1 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+	}
+	return p
+}
+
+func gfPow(x byte, n int) byte {
+	r := byte(1)
+	for ; n > 0; n-- {
+		r = gfMul(r, x)
+	}
+	return r
+}
+
+// aesRound applies one AES round (SubBytes, ShiftRows, MixColumns and
+// AddRoundKey) to a state of four little-endian column words.
+func aesRound(s *[4]uint32, key [4]uint32) {
+	var t [4]uint32
+	for c := range t {
+		t[c] = aesT[0][byte(s[c])] ^ aesT[1][byte(s[(c+1)%4]>>8)] ^
+			aesT[2][byte(s[(c+2)%4]>>16)] ^ aesT[3][byte(s[(c+3)%4]>>24)] ^ key[c]
+	}
+	*s = t
+}
+
+// aesWords loads 16 bytes as four little-endian column words.
+func aesWords(b []byte) [4]uint32 {
+	return [4]uint32{
+		binary.LittleEndian.Uint32(b),
+		binary.LittleEndian.Uint32(b[4:]),
+		binary.LittleEndian.Uint32(b[8:]),
+		binary.LittleEndian.Uint32(b[12:]),
+	}
+}
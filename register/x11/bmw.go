@@ -0,0 +1,148 @@
+package x11
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// bmw512 returns the Blue Midnight Wish digest of msg, in its tweaked second
+// round version.
+func bmw512(msg []byte) []byte {
+	var h [16]uint64
+	for i := range h {
+		b := uint64(0x80 + 8*i)
+		for j := uint64(0); j < 8; j++ {
+			h[i] = h[i]<<8 | (b + j)
+		}
+	}
+	bitLen := uint64(len(msg)) * 8
+
+	for ; len(msg) >= 128; msg = msg[128:] {
+		h = bmwCompress(&h, msg)
+	}
+	var pad [256]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	blocks := pad[:128]
+	if n >= 120 {
+		blocks = pad[:256]
+	}
+	binary.LittleEndian.PutUint64(blocks[len(blocks)-8:], bitLen)
+	for ; len(blocks) > 0; blocks = blocks[128:] {
+		h = bmwCompress(&h, blocks)
+	}
+
+	// The final compression uses the chaining value as the message.
+	var final [16]uint64
+	var block [128]byte
+	for i := range final {
+		final[i] = 0xaaaaaaaaaaaaaaa0 + uint64(i)
+		binary.LittleEndian.PutUint64(block[8*i:], h[i])
+	}
+	h = bmwCompress(&final, block[:])
+
+	out := make([]byte, 64)
+	for i, v := range h[8:] {
+		binary.LittleEndian.PutUint64(out[8*i:], v)
+	}
+	return out
+}
+
+// bmwR holds the rotations r1 to r7 of expand2.
+var bmwR = [7]int{5, 11, 27, 32, 37, 43, 53}
+
+func bmwS(i int, x uint64) uint64 {
+	switch i {
+	case 0:
+		return x>>1 ^ x<<3 ^ bits.RotateLeft64(x, 4) ^ bits.RotateLeft64(x, 37)
+	case 1:
+		return x>>1 ^ x<<2 ^ bits.RotateLeft64(x, 13) ^ bits.RotateLeft64(x, 43)
+	case 2:
+		return x>>2 ^ x<<1 ^ bits.RotateLeft64(x, 19) ^ bits.RotateLeft64(x, 53)
+	case 3:
+		return x>>2 ^ x<<2 ^ bits.RotateLeft64(x, 28) ^ bits.RotateLeft64(x, 59)
+	case 4:
+		return x>>1 ^ x
+	default:
+		return x>>2 ^ x
+	}
+}
+
+func bmwCompress(h *[16]uint64, block []byte) [16]uint64 {
+	var m, x [16]uint64
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint64(block[8*i:])
+		x[i] = m[i] ^ h[i]
+	}
+
+	// f0: the bijective transform of the message and chaining value.
+	var q [32]uint64
+	w := [16]uint64{
+		x[5] - x[7] + x[10] + x[13] + x[14],
+		x[6] - x[8] + x[11] + x[14] - x[15],
+		x[0] + x[7] + x[9] - x[12] + x[15],
+		x[0] - x[1] + x[8] - x[10] + x[13],
+		x[1] + x[2] + x[9] - x[11] - x[14],
+		x[3] - x[2] + x[10] - x[12] + x[15],
+		x[4] - x[0] - x[3] - x[11] + x[13],
+		x[1] - x[4] - x[5] - x[12] - x[14],
+		x[2] - x[5] - x[6] + x[13] - x[15],
+		x[0] - x[3] + x[6] - x[7] + x[14],
+		x[8] - x[1] - x[4] - x[7] + x[15],
+		x[8] - x[0] - x[2] - x[5] + x[9],
+		x[1] + x[3] - x[6] - x[9] + x[10],
+		x[2] + x[4] + x[7] + x[10] + x[11],
+		x[3] - x[5] + x[8] - x[11] - x[12],
+		x[12] - x[4] - x[6] - x[9] + x[13],
+	}
+	for j := range w {
+		q[j] = bmwS(j%5, w[j]) + h[(j+1)%16]
+	}
+
+	// f1: the message expansion, two rounds of expand1 and fourteen of
+	// expand2.
+	for j := 16; j < 32; j++ {
+		k := j - 16
+		add := bits.RotateLeft64(m[k], k+1) + bits.RotateLeft64(m[(k+3)%16], (k+3)%16+1) -
+			bits.RotateLeft64(m[(k+10)%16], (k+10)%16+1) + uint64(j)*0x0555555555555555
+		q[j] = add ^ h[(k+7)%16]
+		if j < 18 {
+			for i := 0; i < 16; i++ {
+				q[j] += bmwS((i+1)%4, q[k+i])
+			}
+		} else {
+			for i := 0; i < 14; i += 2 {
+				q[j] += q[k+i] + bits.RotateLeft64(q[k+i+1], bmwR[i/2])
+			}
+			q[j] += bmwS(4, q[j-2]) + bmwS(5, q[j-1])
+		}
+	}
+
+	// f2: folding into the new chaining value.
+	var xl, xh uint64
+	for _, v := range q[16:24] {
+		xl ^= v
+	}
+	xh = xl
+	for _, v := range q[24:] {
+		xh ^= v
+	}
+	var out [16]uint64
+	out[0] = (xh<<5 ^ q[16]>>5 ^ m[0]) + (xl ^ q[24] ^ q[0])
+	out[1] = (xh>>7 ^ q[17]<<8 ^ m[1]) + (xl ^ q[25] ^ q[1])
+	out[2] = (xh>>5 ^ q[18]<<5 ^ m[2]) + (xl ^ q[26] ^ q[2])
+	out[3] = (xh>>1 ^ q[19]<<5 ^ m[3]) + (xl ^ q[27] ^ q[3])
+	out[4] = (xh>>3 ^ q[20] ^ m[4]) + (xl ^ q[28] ^ q[4])
+	out[5] = (xh<<6 ^ q[21]>>6 ^ m[5]) + (xl ^ q[29] ^ q[5])
+	out[6] = (xh>>4 ^ q[22]<<6 ^ m[6]) + (xl ^ q[30] ^ q[6])
+	out[7] = (xh>>11 ^ q[23]<<2 ^ m[7]) + (xl ^ q[31] ^ q[7])
+	out[8] = bits.RotateLeft64(out[4], 9) + (xh ^ q[24] ^ m[8]) + (xl<<8 ^ q[23] ^ q[8])
+	out[9] = bits.RotateLeft64(out[5], 10) + (xh ^ q[25] ^ m[9]) + (xl>>6 ^ q[16] ^ q[9])
+	out[10] = bits.RotateLeft64(out[6], 11) + (xh ^ q[26] ^ m[10]) + (xl<<6 ^ q[17] ^ q[10])
+	out[11] = bits.RotateLeft64(out[7], 12) + (xh ^ q[27] ^ m[11]) + (xl<<4 ^ q[18] ^ q[11])
+	out[12] = bits.RotateLeft64(out[0], 13) + (xh ^ q[28] ^ m[12]) + (xl>>3 ^ q[19] ^ q[12])
+	out[13] = bits.RotateLeft64(out[1], 14) + (xh ^ q[29] ^ m[13]) + (xl>>4 ^ q[20] ^ q[13])
+	out[14] = bits.RotateLeft64(out[2], 15) + (xh ^ q[30] ^ m[14]) + (xl>>7 ^ q[21] ^ q[14])
+	out[15] = bits.RotateLeft64(out[3], 16) + (xh ^ q[31] ^ m[15]) + (xl>>2 ^ q[22] ^ q[15])
+	return out
+}
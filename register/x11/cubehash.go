@@ -0,0 +1,80 @@
+package x11
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// cubehashIV is the state of CubeHash16/32-512 after its 160 setup rounds,
+// computed in setup.
+var cubehashIV [32]uint32
+
+func cubehashSetup() {
+	cubehashIV[0] = 64
+	cubehashIV[1] = 32
+	cubehashIV[2] = 16
+	cubehashRounds(&cubehashIV, 160)
+}
+
+// cubehash512 returns the CubeHash16/32-512 digest of msg.
+func cubehash512(msg []byte) []byte {
+	x := cubehashIV
+	for ; len(msg) >= 32; msg = msg[32:] {
+		cubehashBlock(&x, msg)
+	}
+
+	var pad [32]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	cubehashBlock(&x, pad[:])
+
+	x[31] ^= 1
+	cubehashRounds(&x, 160)
+
+	out := make([]byte, 64)
+	for i := 0; i < 16; i++ {
+		binary.LittleEndian.PutUint32(out[4*i:], x[i])
+	}
+	return out
+}
+
+func cubehashBlock(x *[32]uint32, block []byte) {
+	for i := 0; i < 8; i++ {
+		x[i] ^= binary.LittleEndian.Uint32(block[4*i:])
+	}
+	cubehashRounds(x, 16)
+}
+
+func cubehashRounds(x *[32]uint32, n int) {
+	for ; n > 0; n-- {
+		for i := 0; i < 16; i++ {
+			x[i+16] += x[i]
+			x[i] = bits.RotateLeft32(x[i], 7)
+		}
+		for i := 0; i < 8; i++ {
+			x[i], x[i+8] = x[i+8], x[i]
+		}
+		for i := 0; i < 16; i++ {
+			x[i] ^= x[i+16]
+		}
+		for i := 16; i < 32; i += 4 {
+			x[i], x[i+2] = x[i+2], x[i]
+			x[i+1], x[i+3] = x[i+3], x[i+1]
+		}
+		for i := 0; i < 16; i++ {
+			x[i+16] += x[i]
+			x[i] = bits.RotateLeft32(x[i], 11)
+		}
+		for i := 0; i < 16; i += 8 {
+			for k := i; k < i+4; k++ {
+				x[k], x[k+4] = x[k+4], x[k]
+			}
+		}
+		for i := 0; i < 16; i++ {
+			x[i] ^= x[i+16]
+		}
+		for i := 16; i < 32; i += 2 {
+			x[i], x[i+1] = x[i+1], x[i]
+		}
+	}
+}
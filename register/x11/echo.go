@@ -0,0 +1,101 @@
+package x11
+
+import "encoding/binary"
+
+// echo512 returns the ECHO-512 digest of msg.
+func echo512(msg []byte) []byte {
+	var v [8][4]uint32
+	for i := range v {
+		v[i][0] = 512
+	}
+
+	var bitLen uint64
+	for ; len(msg) >= 128; msg = msg[128:] {
+		bitLen += 1024
+		echoCompress(&v, msg, bitLen)
+	}
+	bitLen += uint64(len(msg)) * 8
+
+	// The last block carries the digest size and the message length. A
+	// block holding no message bits is compressed with a zero counter.
+	var pad [256]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	blocks := pad[:128]
+	if n >= 110 {
+		blocks = pad[:256]
+	}
+	binary.LittleEndian.PutUint16(blocks[len(blocks)-18:], 512)
+	binary.LittleEndian.PutUint64(blocks[len(blocks)-16:], bitLen)
+	switch {
+	case n == 0:
+		echoCompress(&v, blocks, 0)
+	case len(blocks) == 128:
+		echoCompress(&v, blocks, bitLen)
+	default:
+		echoCompress(&v, blocks[:128], bitLen)
+		echoCompress(&v, blocks[128:], 0)
+	}
+
+	out := make([]byte, 0, 64)
+	for i := 0; i < 4; i++ {
+		for _, x := range v[i] {
+			out = binary.LittleEndian.AppendUint32(out, x)
+		}
+	}
+	return out
+}
+
+// echoCompress runs the ten rounds of BIG.SubWords, BIG.ShiftRows and
+// BIG.MixColumns over the chaining value and a 128-byte block, words
+// being stored by column.
+func echoCompress(v *[8][4]uint32, block []byte, counter uint64) {
+	var w [16][4]uint32
+	copy(w[:8], v[:])
+	for i := 0; i < 8; i++ {
+		w[8+i] = aesWords(block[16*i:])
+	}
+
+	k := [2]uint64{counter, 0}
+	for r := 0; r < 10; r++ {
+		for i := range w {
+			aesRound(&w[i], [4]uint32{uint32(k[0]), uint32(k[0] >> 32), uint32(k[1]), uint32(k[1] >> 32)})
+			aesRound(&w[i], [4]uint32{})
+			k[0]++
+			if k[0] == 0 {
+				k[1]++
+			}
+		}
+
+		var t [16][4]uint32
+		for c := 0; c < 4; c++ {
+			for row := 0; row < 4; row++ {
+				t[4*c+row] = w[4*((c+row)%4)+row]
+			}
+		}
+
+		for c := 0; c < 16; c += 4 {
+			for j := 0; j < 4; j++ {
+				w0, w1, w2, w3 := t[c][j], t[c+1][j], t[c+2][j], t[c+3][j]
+				a0, a1, a2 := w0^w1, w1^w2, w2^w3
+				b0, b1, b2 := echoXtime(a0), echoXtime(a1), echoXtime(a2)
+				w[c][j] = b0 ^ a1 ^ w3
+				w[c+1][j] = b1 ^ w0 ^ a2
+				w[c+2][j] = b2 ^ a0 ^ w3
+				w[c+3][j] = b0 ^ b1 ^ b2 ^ a0 ^ w2
+			}
+		}
+	}
+
+	for i := range v {
+		m := aesWords(block[16*i:])
+		for j := range v[i] {
+			v[i][j] ^= m[j] ^ w[i][j] ^ w[i+8][j]
+		}
+	}
+}
+
+// echoXtime doubles each byte of x in GF(2^8).
+func echoXtime(x uint32) uint32 {
+	return (x&0x80808080)>>7*0x1b ^ (x&0x7f7f7f7f)<<1
+}
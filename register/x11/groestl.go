@@ -0,0 +1,118 @@
+package x11
+
+import "encoding/binary"
+
+// groestlState is the 1024-bit state of Grøstl-512, in columns of 8 bytes.
+type groestlState [16][8]byte
+
+var (
+	// groestlShiftP and groestlShiftQ are the ShiftBytes offsets of each row.
+	groestlShiftP = [8]int{0, 1, 2, 3, 4, 5, 6, 11}
+	groestlShiftQ = [8]int{1, 3, 5, 11, 0, 2, 4, 6}
+	// groestlMix is the first row of the circulant MixBytes matrix, and
+	// groestlMul its multiplication tables.
+	groestlMix = [8]byte{2, 2, 3, 4, 5, 3, 5, 7}
+	groestlMul [8][256]byte
+)
+
+func groestlSetup() {
+	for i, m := range groestlMix {
+		for x := range groestlMul[i] {
+			groestlMul[i][x] = gfMul(m, byte(x))
+		}
+	}
+}
+
+// groestl512 returns the Grøstl-512 digest of msg, in its tweaked final
+// round version.
+func groestl512(msg []byte) []byte {
+	var h groestlState
+	h[15][6] = 0x02 // the output size in bits, 512.
+
+	blocks := uint64(len(msg)/128) + 1
+	for ; len(msg) >= 128; msg = msg[128:] {
+		groestlCompress(&h, msg)
+	}
+	var pad [256]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	last := pad[:128]
+	if n >= 120 {
+		last = pad[:256]
+		blocks++
+	}
+	binary.BigEndian.PutUint64(last[len(last)-8:], blocks)
+	for ; len(last) > 0; last = last[128:] {
+		groestlCompress(&h, last)
+	}
+
+	// The output transformation truncates P(h) ^ h.
+	p := h
+	groestlPermute(&p, false)
+	out := make([]byte, 0, 64)
+	for c := 8; c < 16; c++ {
+		for r := range p[c] {
+			out = append(out, p[c][r]^h[c][r])
+		}
+	}
+	return out
+}
+
+// groestlCompress computes P(h ^ m) ^ Q(m) ^ h.
+func groestlCompress(h *groestlState, block []byte) {
+	var p, q groestlState
+	for c := range q {
+		copy(q[c][:], block[8*c:])
+		for r := range p[c] {
+			p[c][r] = h[c][r] ^ q[c][r]
+		}
+	}
+	groestlPermute(&p, false)
+	groestlPermute(&q, true)
+	for c := range h {
+		for r := range h[c] {
+			h[c][r] ^= p[c][r] ^ q[c][r]
+		}
+	}
+}
+
+// groestlPermute applies the 14 rounds of P1024, or of Q1024 if q is set.
+func groestlPermute(s *groestlState, q bool) {
+	shift := &groestlShiftP
+	if q {
+		shift = &groestlShiftQ
+	}
+	for round := byte(0); round < 14; round++ {
+		// AddRoundConstant.
+		for c := range s {
+			k := byte(c)<<4 ^ round
+			if q {
+				for r := range s[c] {
+					s[c][r] ^= 0xff
+				}
+				s[c][7] ^= k
+			} else {
+				s[c][0] ^= k
+			}
+		}
+
+		// SubBytes and ShiftBytes.
+		var t groestlState
+		for c := range t {
+			for r := range t[c] {
+				t[c][r] = sbox[s[(c+shift[r])%16][r]]
+			}
+		}
+
+		// MixBytes.
+		for c := range s {
+			for r := range s[c] {
+				var v byte
+				for k := range t[c] {
+					v ^= groestlMul[(k-r+8)%8][t[c][k]]
+				}
+				s[c][r] = v
+			}
+		}
+	}
+}
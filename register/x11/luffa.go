@@ -0,0 +1,159 @@
+package x11
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// luffaIV holds the starting values of the five sub-states of Luffa-512.
+var luffaIV = [5][8]uint32{
+	{0x6d251e69, 0x44b051e0, 0x4eaa6fb4, 0xdbf78465, 0x6e292011, 0x90152df4, 0xee058139, 0xdef610bb},
+	{0xc3b44b95, 0xd9d2f256, 0x70eee9a0, 0xde099fa3, 0x5d9b0557, 0x8fc944b3, 0xcf1ccf0e, 0x746cd581},
+	{0xf7efc89d, 0x5dba5781, 0x04016ce5, 0xad659c05, 0x0306194f, 0x666d1836, 0x24aa230a, 0x8b264ae7},
+	{0x858075d5, 0x36d79cce, 0xe571f7d7, 0x204b1f67, 0x35870c6a, 0x57e9e923, 0x14bcb808, 0x7cde72ce},
+	{0x6c68e9be, 0x5ec41e22, 0xc825b7c7, 0xaffb4363, 0xf5df3999, 0x0fc688f1, 0xb07224cc, 0x03e86cea},
+}
+
+// luffaRC holds, for each sub-state permutation and step, the constants
+// added to words 0 and 4.
+var luffaRC = [5][8][2]uint32{
+	{
+		{0x303994a6, 0xe0337818}, {0xc0e65299, 0x441ba90d}, {0x6cc33a12, 0x7f34d442}, {0xdc56983e, 0x9389217f},
+		{0x1e00108f, 0xe5a8bce6}, {0x7800423d, 0x5274baf4}, {0x8f5b7882, 0x26889ba7}, {0x96e1db12, 0x9a226e9d},
+	},
+	{
+		{0xb6de10ed, 0x01685f3d}, {0x70f47aae, 0x05a17cf4}, {0x0707a3d4, 0xbd09caca}, {0x1c1e8f51, 0xf4272b28},
+		{0x707a3d45, 0x144ae5cc}, {0xaeb28562, 0xfaa7ae2b}, {0xbaca1589, 0x2e48f1c1}, {0x40a46f3e, 0xb923c704},
+	},
+	{
+		{0xfc20d9d2, 0xe25e72c1}, {0x34552e25, 0xe623bb72}, {0x7ad8818f, 0x5c58a4a4}, {0x8438764a, 0x1e38e2e7},
+		{0xbb6de032, 0x78e38b9d}, {0xedb780c8, 0x27586719}, {0xd9847356, 0x36eda57f}, {0xa2c78434, 0x703aace7},
+	},
+	{
+		{0xb213afa5, 0xe028c9bf}, {0xc84ebe95, 0x44756f91}, {0x4e608a22, 0x7e8fce32}, {0x56d858fe, 0x956548be},
+		{0x343b138f, 0xfe191be2}, {0xd0ec4e3d, 0x3cb226e5}, {0x2ceb4882, 0x5944a28e}, {0xb3ad2208, 0xa1c4c355},
+	},
+	{
+		{0xf0d2e9e3, 0x5090d577}, {0xac11d7fa, 0x2d1925ab}, {0x1bcb66f2, 0xb46496ac}, {0x6f2d9bc9, 0xd1925ab0},
+		{0x78602649, 0x29131ab6}, {0x8edae952, 0x0fc053c3}, {0x3b6ba548, 0x3f014f0c}, {0xedae9520, 0xfc053c31},
+	},
+}
+
+// luffa512 returns the Luffa-512 digest of msg.
+func luffa512(msg []byte) []byte {
+	v := luffaIV
+	for ; len(msg) >= 32; msg = msg[32:] {
+		luffaRound(&v, msg)
+	}
+
+	var pad [32]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	luffaRound(&v, pad[:])
+
+	// Each half of the output follows a blank round.
+	out := make([]byte, 0, 64)
+	for i := 0; i < 2; i++ {
+		luffaRound(&v, make([]byte, 32))
+		for k := 0; k < 8; k++ {
+			out = binary.BigEndian.AppendUint32(out, v[0][k]^v[1][k]^v[2][k]^v[3][k]^v[4][k])
+		}
+	}
+	return out
+}
+
+// luffaMul2 multiplies w by x in the ring of the message injection.
+func luffaMul2(w [8]uint32) [8]uint32 {
+	t := w[7]
+	return [8]uint32{t, w[0] ^ t, w[1], w[2] ^ t, w[3] ^ t, w[4], w[5], w[6]}
+}
+
+func luffaXor(a, b [8]uint32) [8]uint32 {
+	for k := range a {
+		a[k] ^= b[k]
+	}
+	return a
+}
+
+// luffaRound injects a 32-byte block into the sub-states and permutes
+// each of them.
+func luffaRound(v *[5][8]uint32, block []byte) {
+	a := v[0]
+	for j := 1; j < 5; j++ {
+		a = luffaXor(a, v[j])
+	}
+	a = luffaMul2(a)
+	for j := range v {
+		v[j] = luffaXor(v[j], a)
+	}
+
+	b := luffaXor(luffaMul2(v[0]), v[1])
+	v[1] = luffaXor(luffaMul2(v[1]), v[2])
+	v[2] = luffaXor(luffaMul2(v[2]), v[3])
+	v[3] = luffaXor(luffaMul2(v[3]), v[4])
+	v[4] = luffaXor(luffaMul2(v[4]), v[0])
+	v[0] = luffaXor(luffaMul2(b), v[4])
+	v[4] = luffaXor(luffaMul2(v[4]), v[3])
+	v[3] = luffaXor(luffaMul2(v[3]), v[2])
+	v[2] = luffaXor(luffaMul2(v[2]), v[1])
+	v[1] = luffaXor(luffaMul2(v[1]), b)
+
+	var m [8]uint32
+	for k := range m {
+		m[k] = binary.BigEndian.Uint32(block[4*k:])
+	}
+	for j := range v {
+		if j > 0 {
+			m = luffaMul2(m)
+		}
+		v[j] = luffaXor(v[j], m)
+	}
+
+	for j := range v {
+		luffaPermute(&v[j], j)
+	}
+}
+
+// luffaPermute applies the step function of sub-state j eight times,
+// after the tweak that rotates its upper words by j bits.
+func luffaPermute(w *[8]uint32, j int) {
+	for k := 4; k < 8; k++ {
+		w[k] = bits.RotateLeft32(w[k], j)
+	}
+	for r := 0; r < 8; r++ {
+		luffaSubCrumb(&w[0], &w[1], &w[2], &w[3])
+		luffaSubCrumb(&w[5], &w[6], &w[7], &w[4])
+		for k := 0; k < 4; k++ {
+			u, l := w[k], w[k+4]
+			l ^= u
+			u = bits.RotateLeft32(u, 2) ^ l
+			l = bits.RotateLeft32(l, 14) ^ u
+			u = bits.RotateLeft32(u, 10) ^ l
+			l = bits.RotateLeft32(l, 1)
+			w[k], w[k+4] = u, l
+		}
+		w[0] ^= luffaRC[j][r][0]
+		w[4] ^= luffaRC[j][r][1]
+	}
+}
+
+// luffaSubCrumb is the bitsliced 4-bit S-box of Luffa.
+func luffaSubCrumb(a0, a1, a2, a3 *uint32) {
+	t := *a0
+	*a0 |= *a1
+	*a2 ^= *a3
+	*a1 = ^*a1
+	*a0 ^= *a3
+	*a3 &= t
+	*a1 ^= *a3
+	*a3 ^= *a2
+	*a2 &= *a0
+	*a0 = ^*a0
+	*a2 ^= *a1
+	*a1 |= *a3
+	t ^= *a1
+	*a3 ^= *a2
+	*a2 &= *a1
+	*a1 ^= *a0
+	*a0 = t
+}
@@ -0,0 +1,103 @@
+/*
+This package has no purpose except to perform registration of multihashes.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/x11"
+	)
+
+This package registers X11, the proof-of-work hash of Dash. It chains eleven
+512-bit SHA-3 candidates, each hashing the digest of the one before: BLAKE,
+BMW, Grøstl, Skein, JH, Keccak, Luffa, CubeHash, SHAvite-3, SIMD and ECHO.
+The multihash digest is the first 32 bytes of the ECHO output.
+
+Skein-512 is taken from register/skein, so importing this package registers
+the skein family too.
+*/
+package x11
+
+import (
+	"hash"
+	"sync"
+
+	multihash "github.com/multiformats/go-multihash/core"
+	"github.com/multiformats/go-multihash/internal/keccak"
+	"github.com/multiformats/go-multihash/register/skein"
+)
+
+// Size is the size of an X11 digest, in bytes.
+const Size = 32
+
+func init() {
+	multihash.Register(multihash.X11, New)
+	multihash.RegisterStrength(multihash.X11, multihash.Strength{Bits: 128, Cryptographic: true})
+}
+
+// chain lists the functions of X11 in the order they are applied.
+var chain = []func([]byte) []byte{
+	blake512, bmw512, groestl512, skein512, jh512, keccak512,
+	luffa512, cubehash512, shavite512, simd512, echo512,
+}
+
+var setupOnce sync.Once
+
+// setup builds the tables and initial values of the chained functions. It
+// runs on first use rather than at init, so that importing the package costs
+// nothing.
+func setup() {
+	setupOnce.Do(func() {
+		aesSetup()
+		groestlSetup()
+		jhSetup()
+		cubehashSetup()
+		simdSetup()
+	})
+}
+
+// Sum returns the X11 digest of data.
+func Sum(data []byte) []byte {
+	setup()
+	for _, f := range chain {
+		data = f(data)
+	}
+	return data[:Size]
+}
+
+// New returns a hash.Hash computing X11. None of the chained functions can
+// start before the previous one has finished, so it buffers its input.
+func New() hash.Hash {
+	return &digest{}
+}
+
+type digest struct {
+	buf []byte
+}
+
+func (d *digest) Write(p []byte) (int, error) {
+	d.buf = append(d.buf, p...)
+	return len(p), nil
+}
+
+func (d *digest) Sum(b []byte) []byte {
+	return append(b, Sum(d.buf)...)
+}
+
+func (d *digest) Reset()         { d.buf = d.buf[:0] }
+func (d *digest) Size() int      { return Size }
+func (d *digest) BlockSize() int { return 128 }
+
+func skein512(msg []byte) []byte {
+	h, err := skein.New(skein.Size512, 64)
+	if err != nil {
+		panic(err)
+	}
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+func keccak512(msg []byte) []byte {
+	h := keccak.NewLegacyKeccak(64)
+	h.Write(msg)
+	return h.Sum(nil)
+}
@@ -0,0 +1,120 @@
+package x11
+
+import "encoding/binary"
+
+// shaviteIV is the initial value of SHAvite-3-512.
+var shaviteIV = [16]uint32{
+	0x72fccdd8, 0x79ca4727, 0x128a077b, 0x40d55aec, 0xd1901a06, 0x430ae307, 0xb29f5cd1, 0xdf07fbfc,
+	0x8e45d73d, 0x681ab538, 0xbde86578, 0xdd577e47, 0xe275eade, 0x502d9fcd, 0xb9357178, 0x022a4b9a,
+}
+
+// shavite512 returns the SHAvite-3-512 digest of msg, in its second-round
+// version.
+func shavite512(msg []byte) []byte {
+	h := shaviteIV
+	var bitLen uint64
+	for ; len(msg) >= 128; msg = msg[128:] {
+		bitLen += 1024
+		shaviteCompress(&h, msg, bitLen)
+	}
+	bitLen += uint64(len(msg)) * 8
+
+	// The last block carries the message length and the digest size. A
+	// block holding only padding is compressed with a zero counter.
+	var pad [256]byte
+	n := copy(pad[:], msg)
+	pad[n] = 0x80
+	blocks := pad[:128]
+	if n >= 110 {
+		blocks = pad[:256]
+	}
+	binary.LittleEndian.PutUint64(blocks[len(blocks)-18:], bitLen)
+	binary.LittleEndian.PutUint16(blocks[len(blocks)-2:], 512)
+	switch {
+	case n == 0:
+		shaviteCompress(&h, blocks, 0)
+	case len(blocks) == 128:
+		shaviteCompress(&h, blocks, bitLen)
+	default:
+		shaviteCompress(&h, blocks[:128], bitLen)
+		shaviteCompress(&h, blocks[128:], 0)
+	}
+
+	out := make([]byte, 64)
+	for i, w := range h {
+		binary.LittleEndian.PutUint32(out[4*i:], w)
+	}
+	return out
+}
+
+// shaviteCompress is the C512 compression function, keyed by a 128-byte
+// block and the bit counter.
+func shaviteCompress(h *[16]uint32, block []byte, counter uint64) {
+	cnt := [4]uint32{uint32(counter), uint32(counter >> 32), 0, 0}
+
+	// The key schedule alternates eight nonlinear steps, built on keyless
+	// AES rounds, with eight linear ones. The counter enters at four
+	// fixed places.
+	var rk [448]uint32
+	for i := 0; i < 32; i++ {
+		rk[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	for i := 32; i < 448; i += 4 {
+		if (i-32)%64 >= 32 {
+			for k := 0; k < 4; k++ {
+				rk[i+k] = rk[i+k-32] ^ rk[i+k-7]
+			}
+			continue
+		}
+		t := [4]uint32{rk[i-31], rk[i-30], rk[i-29], rk[i-32]}
+		aesRound(&t, [4]uint32{})
+		for k := 0; k < 4; k++ {
+			rk[i+k] = t[k] ^ rk[i+k-4]
+		}
+		var c [4]uint32
+		switch i {
+		case 32:
+			c = [4]uint32{cnt[0], cnt[1], cnt[2], ^cnt[3]}
+		case 164:
+			c = [4]uint32{cnt[3], cnt[2], cnt[1], ^cnt[0]}
+		case 316:
+			c = [4]uint32{cnt[2], cnt[3], cnt[0], ^cnt[1]}
+		case 440:
+			c = [4]uint32{cnt[1], cnt[0], cnt[3], ^cnt[2]}
+		default:
+			continue
+		}
+		for k := 0; k < 4; k++ {
+			rk[i+k] ^= c[k]
+		}
+	}
+
+	// Each of the 14 rounds runs two four-round AES Feistel branches over
+	// the four 128-bit quarters of the state, then rotates the quarters.
+	var p [4][4]uint32
+	for i := range p {
+		copy(p[i][:], h[4*i:])
+	}
+	key := rk[:]
+	for r := 0; r < 14; r++ {
+		for _, b := range [2][2]int{{1, 0}, {3, 2}} {
+			t := p[b[0]]
+			for j := 0; j < 4; j++ {
+				for k := range t {
+					t[k] ^= key[k]
+				}
+				aesRound(&t, [4]uint32{})
+				key = key[4:]
+			}
+			for k := range t {
+				p[b[1]][k] ^= t[k]
+			}
+		}
+		p[0], p[1], p[2], p[3] = p[3], p[0], p[1], p[2]
+	}
+	for i := range p {
+		for k := range p[i] {
+			h[4*i+k] ^= p[i][k]
+		}
+	}
+}
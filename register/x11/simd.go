@@ -0,0 +1,147 @@
+package x11
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// simdIV is the initial value of SIMD-512.
+var simdIV = [32]uint32{
+	0x0ba16b95, 0x72f999ad, 0x9fecc2ae, 0xba3264fc, 0x5e894929, 0x8e9f30e5, 0x2f1daa37, 0xf0f2c558,
+	0xac506643, 0xa90635a5, 0xe25b878b, 0xaab7878f, 0x88817f7a, 0x0a02892b, 0x559a7550, 0x598f657e,
+	0x7eef60a1, 0x6b70e3e8, 0x9c1714d1, 0xb958e2a8, 0xab02675e, 0xed1c014f, 0xcd8d65bb, 0xfdb7a257,
+	0x09254899, 0xd699c7bc, 0x9019b6dc, 0x2b9022e4, 0x8fa14956, 0x21bf9bd3, 0xb94d0943, 0x6ffddc22,
+}
+
+// simdAlpha holds the powers of 41, a 256th root of unity modulo 257.
+var simdAlpha [256]int32
+
+func simdSetup() {
+	simdAlpha[0] = 1
+	for i := 1; i < 256; i++ {
+		simdAlpha[i] = simdAlpha[i-1] * 41 % 257
+	}
+}
+
+// simdPerm gives, for each step modulo 7, the mask that picks the lane
+// whose rotated A word is added in.
+var simdPerm = [7]int{1, 6, 2, 3, 5, 7, 4}
+
+// simdRot holds the rotation amounts of the four rounds.
+var simdRot = [4][4]int{
+	{3, 23, 17, 27},
+	{28, 19, 22, 7},
+	{29, 9, 15, 5},
+	{4, 13, 10, 25},
+}
+
+// simdSteps gives, for each round, the sixteen-coefficient groups of the
+// expanded message feeding its eight steps.
+var simdSteps = [4][8]int{
+	{4, 6, 0, 2, 7, 5, 3, 1},
+	{15, 11, 12, 8, 9, 13, 10, 14},
+	{17, 18, 23, 20, 22, 21, 16, 19},
+	{30, 24, 25, 31, 27, 29, 28, 26},
+}
+
+// simd512 returns the SIMD-512 digest of msg, in its tweaked second-round
+// version.
+func simd512(msg []byte) []byte {
+	h := simdIV
+	bitLen := uint64(len(msg)) * 8
+	for ; len(msg) >= 128; msg = msg[128:] {
+		simdCompress(&h, msg[:128], false)
+	}
+
+	// A partial block is zero-padded; a final block holding the message
+	// length follows.
+	var block [128]byte
+	if len(msg) > 0 {
+		copy(block[:], msg)
+		simdCompress(&h, block[:], false)
+		block = [128]byte{}
+	}
+	binary.LittleEndian.PutUint64(block[:], bitLen)
+	simdCompress(&h, block[:], true)
+
+	out := make([]byte, 64)
+	for i := 0; i < 16; i++ {
+		binary.LittleEndian.PutUint32(out[4*i:], h[i])
+	}
+	return out
+}
+
+// simdCompress expands a 128-byte block with the number-theoretic
+// transform and mixes it into the four 256-bit registers of the state.
+func simdCompress(h *[32]uint32, block []byte, final bool) {
+	// y holds the transform of the block, with X^255, and X^253 on the
+	// final block, added to the polynomial, centred around zero.
+	var y [256]int32
+	for i := range y {
+		var sum int32
+		for j, x := range block {
+			sum += int32(x) * simdAlpha[i*j%256]
+		}
+		sum += simdAlpha[255*i%256]
+		if final {
+			sum += simdAlpha[253*i%256]
+		}
+		sum %= 257
+		if sum > 128 {
+			sum -= 257
+		}
+		y[i] = sum
+	}
+
+	var st [32]uint32
+	for i := range st {
+		st[i] = h[i] ^ binary.LittleEndian.Uint32(block[4*i:])
+	}
+
+	var w [8]uint32
+	for r := 0; r < 4; r++ {
+		for s := 0; s < 8; s++ {
+			g := 16 * simdSteps[r][s]
+			for k := range w {
+				var lo, hi int32
+				switch r {
+				case 0, 1:
+					lo, hi = y[g+2*k]*185, y[g+2*k+1]*185
+				case 2:
+					lo, hi = y[g+2*k-256]*233, y[g+2*k-128]*233
+				case 3:
+					lo, hi = y[g+2*k-383]*233, y[g+2*k-255]*233
+				}
+				w[k] = uint32(lo)&0xffff | uint32(hi)<<16
+			}
+			rot := simdRot[r]
+			simdStep(&st, w, rot[s%4], rot[(s+1)%4], simdPerm[(8*r+s)%7], s >= 4)
+		}
+	}
+
+	// Four more steps feed the previous chaining value forward.
+	for s := 0; s < 4; s++ {
+		copy(w[:], h[8*s:])
+		rot := simdRot[3]
+		simdStep(&st, w, rot[s], rot[(s+1)%4], simdPerm[(32+s)%7], false)
+	}
+	*h = st
+}
+
+// simdStep runs one step on the eight parallel lanes of A, B, C and D,
+// with the IF Boolean function or, when maj is set, the majority.
+func simdStep(st *[32]uint32, w [8]uint32, r, s, perm int, maj bool) {
+	a, b, c, d := st[0:8], st[8:16], st[16:24], st[24:32]
+	var ra [8]uint32
+	for j := range ra {
+		ra[j] = bits.RotateLeft32(a[j], r)
+	}
+	for j := range ra {
+		f := (b[j]^c[j])&a[j] ^ c[j]
+		if maj {
+			f = a[j]&b[j] | (a[j]|b[j])&c[j]
+		}
+		a[j] = bits.RotateLeft32(d[j]+w[j]+f, s) + ra[j^perm]
+		d[j], c[j], b[j] = c[j], b[j], ra[j]
+	}
+}
@@ -0,0 +1,184 @@
+package x11
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// katMessages are the messages of 0, 8, 512 and 1024 bits from the SHA-3
+// competition's ShortMsgKAT files.
+var katMessages = [4][]byte{
+	nil,
+	mustHexDecode("cc"),
+	mustHexDecode("e926ae8b0af6e53176dbffcc2a6b88c6bd765f939d3d178a9bde9ef3aa131c61" +
+		"e31c1e42cdfaf4b4dcde579a37e150efbef5555b4c1cb40439d835a724e2fae7"),
+	mustHexDecode("2b6db7ced8665ebe9deb080295218426bdaa7c6da9add2088932cdffbaa1c141" +
+		"29bccdd70f369efb149285858d2b1d155d14de2fdb680a8b027284055182a0ca" +
+		"e275234cc9c92863c1b4ab66f304cf0621cd54565f5bff461d3b461bd40df281" +
+		"98e3732501b4860eadd503d26d6e69338f4e0456e9e9baf3d827ae685fb1d817"),
+}
+
+// The digests of katMessages from the ShortMsgKAT_512 file of each
+// submission, in the version X11 uses.
+var katDigests = []struct {
+	name     string
+	sum      func([]byte) []byte
+	expected [4]string
+}{
+	{"BLAKE-512", blake512, [4]string{
+		"a8cfbbd73726062df0c6864dda65defe58ef0cc52a5625090fa17601e1eecd1b628e94f396ae402a00acc9eab77b4d4c2e852aaaa25a636d80af3fc7913ef5b8",
+		"4f0ef594f20172d23504873f596984c64c1583c7b2abb8d8786aa2aeeae1c46c744b61893d661b0733b76d1fe19257dd68e0ef05422ca25d058dfe6c33d68709",
+		"ec1270cb5c96df2106a9c4f694ad6dc8d83a8ae1c375b613a447b95e2a09e76d1a32c73cae58ef8c6822ad7ba50aabba00f01de11ac3606fabb67fadbb5be530",
+		"708dbd20edbd4cb8d1127e8ed75d8b89f7507c15b3eadbc8a2a0a352d8801dfda778d9c0a96b04c517cc8565ba28b6260b788a5ea0c8cd7091d3cc75036b412e",
+	}},
+	{"BMW-512", bmw512, [4]string{
+		"6a725655c42bc8a2a20549dd5a233a6a2beb01616975851fd122504e604b46af7d96697d0b6333db1d1709d6df328d2a6c786551b0cce2255e8c7332b4819c0e",
+		"0309cd7a44e6022671e84c43cdb92f613931d1c6b71467c039034b1263c2bf92203e27604bc53fcea9c2df3b10862c9b6fb6e8c617754ef49a2b80f51c74acd3",
+		"548d7a65d8beebe56c466da17f8dd80722a7a2a59352465a150f58c1cdc75e8049f5734ea16f32f5ce5b339cdfd99d930d20a6b8655b6f20de4e7e7438c405e8",
+		"447c299f7e5c90cee70a7577ec148fef194f40ba7c3c8cbc96ff81d14490a16e397ca01f3c0883e050f805239fbd4189122b45b1101ee1f303281d2ac1580e2c",
+	}},
+	{"Groestl-512", groestl512, [4]string{
+		"6d3ad29d279110eef3adbd66de2a0345a77baede1557f5d099fce0c03d6dc2ba8e6d4a6633dfbd66053c20faa87d1a11f39a7fbe4a6c2f009801370308fc4ad8",
+		"b23eeeb675c272c6e37a6ee9ab4dc505c9d6a10020f6bed3948205d04cdd1e90b06e494d186ef4f19266d7da200c89dc009e2b1a538cdea199e773fc076f802e",
+		"e8eeddef7104f8ee0a93c4be5028db073b7ac44b73787b3adcafdb8aade084c19550ebb1d968e2b26b9f99ba48d5686e1d7658725f6934a845cb5ee475b8b7f0",
+		"ff410b511135dbc0b8644c28efa3ec632326feb98e50edc6390c441610d7c514acdf0a61a0bf01aa9dc1f55d92e085248eba1c24ee23978b4986af41c13a6176",
+	}},
+	{"Skein-512", skein512, [4]string{
+		"bc5b4c50925519c290cc634277ae3d6257212395cba733bbad37a4af0fa06af41fca7903d06564fea7a2d3730dbdb80c1f85562dfcc070334ea4d1d9e72cba7a",
+		"26d8382ebdc39072293ddcdda6568b4add2449a05424a12dfbf11595228e9fbf7c542f25ec0f7348b19ad23ef5e97d45e5cff7bb9969be332923f33be53a6d09",
+		"2df35398690d99075bc67bde85d7cdf512df9f05fff16cfd1aef3f7e641961e60daf81fd8f9a625fe9149866fdc69f73c58aae9f758ab5ea3011c67649e3f0b0",
+		"832fa35e6ad63ab4c1ac025496b38891ab95986a7ae6dedede9a528d3f0ecc93a8c5aa04863487c827a057abeacafe3ce411bd49fffea012f90c086a7e55825e",
+	}},
+	{"JH-512", jh512, [4]string{
+		"90ecf2f76f9d2c8017d979ad5ab96b87d58fc8fc4b83060f3f900774faa2c8fabe69c5f4ff1ec2b61d6b316941cedee117fb04b1f4c5bc1b919ae841c50eec4f",
+		"277c93806945992a7f10102f28471af2783fe32003b3f63320810e74f1bc233bf8669ab4b922db9ef13fcdcd4d31193b731eedde98fc87c129c04a4a1071f66f",
+		"d1dbacb16c6a88bea992cd34f92d1375f05215037cf989e155d324d6d1e4204320cf18c1ad6bf11019cdd112bac3c7cb73e41a94254b8c5af3db8245318ffc70",
+		"9ec6669f30f482a65d93fe7811923b6bdf3a1bde032502e0b1a064c9d893c03507576fc4fbc745d458c1402ba76f7d4c4539c4dfed7c058596fa270416865162",
+	}},
+	{"Keccak-512", keccak512, [4]string{
+		"0eab42de4c3ceb9235fc91acffe746b29c29a8c366b7c60e4e67c466f36a4304c00fa9caf9d87976ba469bcbe06713b435f091ef2769fb160cdab33d3670680e",
+		"8630c13cbd066ea74bbe7fe468fec1dee10edc1254fb4c1b7c5fd69b646e44160b8ce01d05a0908ca790dfb080f4b513bc3b6225ece7a810371441a5ac666eb9",
+		"c0a4d8dca967772dbf6e5508c913e7beba1b749a2b1ac963d0676e6f1dcd4ebaa3f909ef87dd849882dc8253347a5f6520b5b9f510973f443976455f923cfcb9",
+		"aebba57c8ed5af6ec93f4aa45772ff5167b7ea88dfa71364f37d8fc5fdb7dc3b2c8331a08023f21d110b7d821e2dc7e860826235e7e6291912ac521384747354",
+	}},
+	{"Luffa-512", luffa512, [4]string{
+		"6e7de4501189b3ca58f3ac114916654bbcd4922024b4cc1cd764acfe8ab4b7805df133eab345ffdb1c414564c924f48e0a301824e2ac4c34bd4efde2e43da90e",
+		"91f1b09b2842871bc2f069e5d278d2d707ddafabfe3ced5154faf841e96781908290e6533d146183e8b7ec298f6da20e0cfb1d41f4f711a3050faa8dd4641f7f",
+		"e18b08234bed8586b8d40314dc2854086d8d85ddf83b321800b4039bf162fc4ab9229ca3d34f5c554e8409ef70a50c13164d00094142a6139b36e3ab911c81de",
+		"b5ed1243b49849be8e841ba314242ae3b769cfcaf7678358e2bac83f1e08e3c83f53b202ab693e4c390683252d2793d89686670bd474e7b5f542e10949834e33",
+	}},
+	{"CubeHash16/32-512", cubehash512, [4]string{
+		"4a1d00bbcfcb5a9562fb981e7f7db3350fe2658639d948b9d57452c22328bb32f468b072208450bad5ee178271408be0b16e5633ac8a1e3cf9864cfbfc8e043a",
+		"5c3019f2abc3471ed3a19648071cf2311503dc4202508f8d3efcb1023fd895505c4d634c1ae9d9f81de6394690366154c715bf8d68242b2c64e1ebb1e538b330",
+		"c096f535cab880f0d77d6aff91fcbdc863cac17fd5177122e2b9c5a0063a6182dcb1f0086618b80fe4c02872dc3ab6ab8cb2608d19d904935b392c20c520fdba",
+		"7e695c93bd0405b847745082162da8853556a635a82f401b1aef140528d02f6036fc3653b6c19284a59a4821bbd16632cd1847c92238dcbf6cd2a0a510668722",
+	}},
+	{"SHAvite-3-512", shavite512, [4]string{
+		"a485c1b2578459d1efc5dddd840bb0b4a650ac82fe68f58c4442ccda747da006b2d1dc6b4a4eb7d84ff91e1f466fef429d259acd995dddcad16fa545c7a6e5ba",
+		"3fe519289541f0ec62f2247b55844f9dfce6d008c9062e4ae2821a0dd9e47b7e37e9b859e1b2d0e0cf1090c68223034c94314a190b92bf71f3810ee32b2732e6",
+		"70c537a050bfba2d977148e5bf5475774aa6438c0141994bb0f484c00e310d11517511b92a6af06462ba5c89165633efd4ff2ba101e75c171de23e162bb13dff",
+		"14732b9fbf23b86b9ec182a29893de5c58d91f61361cbbedec03220afa974538ac891de4408a0d82de63319fdf23364ea0768920cfab82563358d924b6977ce3",
+	}},
+	{"SIMD-512", simd512, [4]string{
+		"51a5af7e243cd9a5989f7792c880c4c3168c3d60c4518725fe5757d1f7a69c6366977eaba7905ce2da5d7cfd07773725f0935b55f3efb954996689a49b6d29e0",
+		"6fd2d5e6104bd3966283321234cd40f4ed380cb53a03911b610746466c10a93e41c9b745c79dfde3275980fe82fc8372efc406a9b0bdc8c63a375954e63436e2",
+		"f1c23c1573c37f1491606318203057fea497cc96edd0b307365e76f4f80624dc618c1726b37896f79a19ee48801dfe17cbce495c77c2b4ea5d9baee6b3c3f19e",
+		"71dde0f958761d254257b2c1517f5f57c12d78a5f62b830124d99241eb536c7d9a1421d226b92522b287566f26b28670da96cdce7d5179d9ad3c470d98dfad5e",
+	}},
+	{"ECHO-512", echo512, [4]string{
+		"158f58cc79d300a9aa292515049275d051a28ab931726d0ec44bdd9faef4a702c36db9e7922fff077402236465833c5cc76af4efc352b4b44c7fa15aa0ef234e",
+		"dfce37ca6f32ba4c3a72e77bca20e511a39b31a6075815f083db2ecfd5c32cfd6a4e0dd9bd51921199758edd2fe8ed0fa31e06aa821c7030653d15408e8728dd",
+		"ec6e7384fa2c16f125a736f57c936d07044a0ac2ebb3ef4adc2775168f94f7a837dc5125aad549f02af856c19198f0e70f329cade4c319b6ec91c5fc82b6eeb9",
+		"f286dbf18d8320b44098e99be7b974ebdf0da2c7b6e580bce54f6a27e2a114de140e4f49802d79008ec5f405f58f17077f24d5da007a24db96c72dce2343991e",
+	}},
+}
+
+func TestChainedFunctions(t *testing.T) {
+	setup()
+	for _, tc := range katDigests {
+		for i, msg := range katMessages {
+			if result := hex.EncodeToString(tc.sum(msg)); result != tc.expected[i] {
+				t.Errorf("%s of %d bytes: expected %s; got %s", tc.name, len(msg), tc.expected[i], result)
+			}
+		}
+	}
+}
+
+// Genesis block headers of Dash mainnet, testnet and regtest, with their
+// hashes as asserted in Dash Core's chainparams.cpp, in display order.
+var headers = []struct {
+	name, header, hash string
+}{
+	{
+		"mainnet",
+		"01000000000000000000000000000000000000000000000000000000000000000000000" +
+			"0c762a6567f3cc092f0684bb62b7e00a84890b990f07cc71a6bb58d64b98e02e0022ddb52f0ff0f1ec23fb901",
+		"00000ffd590b1485b3caadc19b22e6379c733355108f107a430458cdf3407ab6",
+	},
+	{
+		"testnet",
+		"01000000000000000000000000000000000000000000000000000000000000000000000" +
+			"0c762a6567f3cc092f0684bb62b7e00a84890b990f07cc71a6bb58d64b98e02e0dee1e352f0ff0f1ec3c927e6",
+		"00000bafbc94add76cb75e2ec92894837288a481e5c005f6563d91623bf8bc2c",
+	},
+	{
+		"regtest",
+		"01000000000000000000000000000000000000000000000000000000000000000000000" +
+			"0c762a6567f3cc092f0684bb62b7e00a84890b990f07cc71a6bb58d64b98e02e0b9968054ffff7f20ffba1000",
+		"000008ca1832a4baf228eb1553c03d3a2c8e02399550dd6ea8d65cec3ef23d2e",
+	},
+}
+
+func TestBlockHeaders(t *testing.T) {
+	for _, tc := range headers {
+		digest := Sum(mustHexDecode(tc.header))
+		// Block hashes are displayed as little-endian numbers.
+		for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+			digest[i], digest[j] = digest[j], digest[i]
+		}
+		if result := hex.EncodeToString(digest); result != tc.hash {
+			t.Errorf("%s: expected %s; got %s", tc.name, tc.hash, result)
+		}
+	}
+}
+
+func TestX11(t *testing.T) {
+	// From the tests of github.com/bitbandi/go-x11, a port of sphlib.
+	for _, tc := range []struct {
+		data     string
+		expected []byte
+	}{
+		{"", mustHexDecode("51b572209083576ea221c27e62b4e22063257571ccb6cc3dc3cd17eb67584eba")},
+		{"DASH", mustHexDecode("fe809ebca8753d907f6ad32cdcf8e5c4e090d7bece5df35b2147e10b88c12d26")},
+		{"The quick brown fox jumps over the lazy dog", mustHexDecode("534536a4e4f16b32447f02f77200449dc2f23b532e3d9878fe111c9de666bc5c")},
+	} {
+		h, err := multihash.GetHasher(multihash.X11)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Write in uneven pieces; the digest buffers them.
+		for i := 0; i < len(tc.data); i += 7 {
+			h.Write([]byte(tc.data[i:min(i+7, len(tc.data))]))
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("%q: expected %x; got %x", tc.data, tc.expected, result)
+		}
+
+		h.Reset()
+		h.Write([]byte(tc.data))
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("%q after Reset: expected %x; got %x", tc.data, tc.expected, result)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+This package has no purpose except to perform registration of multihashes.
+
+It is meant to be used as a side-effecting import, e.g.
+
+	import (
+		_ "github.com/multiformats/go-multihash/register/xxhash"
+	)
+
+This package registers the xxHash family: xxh-32, xxh-64, xxh3-64 and
+xxh3-128. Digests are in xxHash's canonical, big-endian form. xxh-64 comes
+from github.com/cespare/xxhash and the xxh3 variants from
+github.com/zeebo/xxh3, both of which use assembly where it is available.
+
+xxHash is not a cryptographic hash: it is registered as non-cryptographic,
+so that VerifyAny in the root package never relies on it when a MinBits
+policy is set.
+*/
+package xxhash
+
+import (
+	"hash"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/zeebo/xxh3"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func init() {
+	RegisterSeed(0)
+
+	multihash.RegisterStrength(multihash.XXH_32, multihash.Strength{Bits: 16})
+	multihash.RegisterStrength(multihash.XXH_64, multihash.Strength{Bits: 32})
+	multihash.RegisterStrength(multihash.XXH3_64, multihash.Strength{Bits: 32})
+	multihash.RegisterStrength(multihash.XXH3_128, multihash.Strength{Bits: 64})
+}
+
+// RegisterSeed replaces the registered xxHash hashers with ones using seed;
+// xxh-32 uses its low 32 bits. The package registers them with a seed of 0.
+//
+// Multihashes do not record the seed, so every party must agree on it. Like
+// the Register function of the core package, RegisterSeed has a global
+// effect and should only be used at init time.
+func RegisterSeed(seed uint64) {
+	multihash.Register(multihash.XXH_32, func() hash.Hash { return New32(uint32(seed)) })
+	multihash.Register(multihash.XXH_64, func() hash.Hash { return New64(seed) })
+	multihash.Register(multihash.XXH3_64, func() hash.Hash { return New3_64(seed) })
+	multihash.Register(multihash.XXH3_128, func() hash.Hash { return New3_128(seed) })
+}
+
+// New64 returns XXH64 with the given seed.
+func New64(seed uint64) hash.Hash64 {
+	return xxhash.NewWithSeed(seed)
+}
+
+// New3_64 returns the 64-bit XXH3 with the given seed.
+func New3_64(seed uint64) hash.Hash64 {
+	return xxh3.NewSeed(seed)
+}
+
+// New3_128 returns the 128-bit XXH3 with the given seed.
+func New3_128(seed uint64) hash.Hash {
+	return xxh3_128{xxh3.NewSeed(seed)}
+}
+
+// A wrapper is needed for the 128-bit digest, as xxh3.Hasher is a 64-bit
+// hash.Hash which only exposes the 128-bit value through Sum128.
+type xxh3_128 struct {
+	*xxh3.Hasher
+}
+
+func (x xxh3_128) Size() int {
+	return 16
+}
+
+func (x xxh3_128) Sum(digest []byte) []byte {
+	sum := x.Sum128().Bytes()
+	return append(digest, sum[:]...)
+}
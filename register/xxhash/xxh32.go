@@ -0,0 +1,107 @@
+package xxhash
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+const (
+	prime32_1 = 2654435761
+	prime32_2 = 2246822519
+	prime32_3 = 3266489917
+	prime32_4 = 668265263
+	prime32_5 = 374761393
+)
+
+// xxh32 is XXH32, which neither of the libraries used for the other
+// variants provides.
+type xxh32 struct {
+	seed uint32
+	v    [4]uint32
+	buf  [16]byte
+	n    int
+	len  uint64
+}
+
+var _ hash.Hash32 = (*xxh32)(nil)
+
+// New32 returns XXH32 with the given seed.
+func New32(seed uint32) hash.Hash32 {
+	d := &xxh32{seed: seed}
+	d.Reset()
+	return d
+}
+
+func (d *xxh32) Size() int { return 4 }
+
+func (d *xxh32) BlockSize() int { return 16 }
+
+func (d *xxh32) Reset() {
+	d.v = [4]uint32{d.seed + prime32_1 + prime32_2, d.seed + prime32_2, d.seed, d.seed - prime32_1}
+	d.n = 0
+	d.len = 0
+}
+
+func round32(acc, in uint32) uint32 {
+	return bits.RotateLeft32(acc+in*prime32_2, 13) * prime32_1
+}
+
+func (d *xxh32) stripe(p []byte) {
+	for i := range d.v {
+		d.v[i] = round32(d.v[i], binary.LittleEndian.Uint32(p[4*i:]))
+	}
+}
+
+func (d *xxh32) Write(p []byte) (int, error) {
+	written := len(p)
+	d.len += uint64(written)
+	if d.n > 0 {
+		k := copy(d.buf[d.n:], p)
+		d.n += k
+		p = p[k:]
+		if d.n < len(d.buf) {
+			return written, nil
+		}
+		d.stripe(d.buf[:])
+		d.n = 0
+	}
+	for ; len(p) >= 16; p = p[16:] {
+		d.stripe(p)
+	}
+	d.n = copy(d.buf[:], p)
+	return written, nil
+}
+
+func (d *xxh32) Sum32() uint32 {
+	var h uint32
+	if d.len >= 16 {
+		h = bits.RotateLeft32(d.v[0], 1) + bits.RotateLeft32(d.v[1], 7) +
+			bits.RotateLeft32(d.v[2], 12) + bits.RotateLeft32(d.v[3], 18)
+	} else {
+		h = d.seed + prime32_5
+	}
+	h += uint32(d.len)
+
+	p := d.buf[:d.n]
+	for ; len(p) >= 4; p = p[4:] {
+		h += binary.LittleEndian.Uint32(p) * prime32_3
+		h = bits.RotateLeft32(h, 17) * prime32_4
+	}
+	for _, b := range p {
+		h += uint32(b) * prime32_5
+		h = bits.RotateLeft32(h, 11) * prime32_1
+	}
+
+	h ^= h >> 15
+	h *= prime32_2
+	h ^= h >> 13
+	h *= prime32_3
+	h ^= h >> 16
+	return h
+}
+
+// Sum appends the digest in its canonical, big-endian form.
+func (d *xxh32) Sum(b []byte) []byte {
+	return binary.BigEndian.AppendUint32(b, d.Sum32())
+}
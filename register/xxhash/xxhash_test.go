@@ -0,0 +1,148 @@
+package xxhash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	multihash "github.com/multiformats/go-multihash/core"
+)
+
+func mustHexDecode(s string) []byte {
+	d, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestXXHash(t *testing.T) {
+	spam := "Nobody inspects the spammish repetition"
+	for _, tc := range []struct {
+		code     uint64
+		data     string
+		expected []byte
+	}{
+		{multihash.XXH_32, "", mustHexDecode("02cc5d05")},
+		{multihash.XXH_32, "a", mustHexDecode("550d7456")},
+		{multihash.XXH_32, "abc", mustHexDecode("32d153ff")},
+		{multihash.XXH_32, spam, mustHexDecode("e2293b2f")},
+		{multihash.XXH_64, "", mustHexDecode("ef46db3751d8e999")},
+		{multihash.XXH_64, "abc", mustHexDecode("44bc2cf5ad770999")},
+		{multihash.XXH_64, spam, mustHexDecode("fbcea83c8a378bf1")},
+		{multihash.XXH3_64, "", mustHexDecode("2d06800538d394c2")},
+		{multihash.XXH3_64, "abc", mustHexDecode("78af5f94892f3950")},
+		{multihash.XXH3_128, "", mustHexDecode("99aa06d3014798d86001c324468d497f")},
+		{multihash.XXH3_128, "abc", mustHexDecode("06b05ab6733a618578af5f94892f3950")},
+	} {
+		h, err := multihash.GetHasher(tc.code)
+		if err != nil {
+			t.Fatalf("0x%x: failed to get: %s", tc.code, err)
+		}
+		if h.Size() != len(tc.expected) {
+			t.Errorf("0x%x: expected size %d; got %d", tc.code, len(tc.expected), h.Size())
+		}
+		h.Write([]byte(tc.data))
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("0x%x: %q: expected %x; got %x", tc.code, tc.data, tc.expected, result)
+		}
+	}
+}
+
+func TestXXH32Seed(t *testing.T) {
+	// The sanity checks of the reference implementation use PRIME32_1 as
+	// their seed.
+	if result := New32(prime32_1).Sum32(); result != 0x36b78ae7 {
+		t.Errorf("expected 36b78ae7; got %08x", result)
+	}
+
+	// Writing in pieces must agree with writing at once, on both sides of
+	// the 16 byte stripes.
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	for n := 0; n <= len(data); n++ {
+		all := New32(7)
+		all.Write(data[:n])
+		pieces := New32(7)
+		for i := 0; i < n; i += 3 {
+			pieces.Write(data[i:min(i+3, n)])
+		}
+		if all.Sum32() != pieces.Sum32() {
+			t.Fatalf("%d bytes: digest depends on how the data was written", n)
+		}
+	}
+}
+
+// prime64_1 is PRIME64_1 of the reference implementation.
+const prime64_1 = 0x9e3779b185ebca8d
+
+// sanityBuffer returns the first n bytes of the buffer hashed by the
+// self-test of the reference implementation (xsum_sanity_check.c).
+func sanityBuffer(n int) []byte {
+	b := make([]byte, n)
+	gen := uint64(prime32_1)
+	for i := range b {
+		b[i] = byte(gen >> 56)
+		gen *= prime64_1
+	}
+	return b
+}
+
+func TestXXH3Seed(t *testing.T) {
+	// Seeded results of the reference implementation's self-test: xxh3-64
+	// uses PRIME64 as its seed and xxh3-128 uses PRIME32.
+	for _, tc := range []struct {
+		n        int
+		expected []byte
+		h        hash.Hash
+	}{
+		{0, mustHexDecode("a8a6b918b2f0364a"), New3_64(prime64_1)},
+		{1, mustHexDecode("032be332dd766ef8"), New3_64(prime64_1)},
+		{6, mustHexDecode("84589c116ab59ab9"), New3_64(prime64_1)},
+		{12, mustHexDecode("e7303e1b2336de0e"), New3_64(prime64_1)},
+		{0, mustHexDecode("92220ae55e14ab505444f7869c671ab0"), New3_128(prime32_1)},
+		{1, mustHexDecode("89b99554ba22467cb53d5557e7f76f8d"), New3_128(prime32_1)},
+		{6, mustHexDecode("5a865b5389abd2b1269d8f70be98856e"), New3_128(prime32_1)},
+	} {
+		tc.h.Write(sanityBuffer(tc.n))
+		if result := tc.h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("%d bytes: expected %x; got %x", tc.n, tc.expected, result)
+		}
+	}
+}
+
+func TestRegisterSeed(t *testing.T) {
+	defer RegisterSeed(0)
+
+	for _, tc := range []struct {
+		seed     uint64
+		code     uint64
+		expected []byte
+	}{
+		{prime32_1, multihash.XXH_32, mustHexDecode("36b78ae7")},
+		{prime32_1, multihash.XXH_64, mustHexDecode("ac75fda2929b17ef")},
+		{prime64_1, multihash.XXH3_64, mustHexDecode("a8a6b918b2f0364a")},
+		{prime32_1, multihash.XXH3_128, mustHexDecode("92220ae55e14ab505444f7869c671ab0")},
+	} {
+		RegisterSeed(tc.seed)
+		h, err := multihash.GetHasher(tc.code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := h.Sum(nil); !bytes.Equal(result, tc.expected) {
+			t.Errorf("0x%x: expected %x; got %x", tc.code, tc.expected, result)
+		}
+	}
+}
+
+func TestNotCryptographic(t *testing.T) {
+	for _, code := range []uint64{multihash.XXH_32, multihash.XXH_64, multihash.XXH3_64, multihash.XXH3_128} {
+		s, ok := multihash.GetStrength(code)
+		if !ok {
+			t.Fatalf("0x%x: no strength registered", code)
+		}
+		if s.Cryptographic || s.EffectiveBits(16) != 0 {
+			t.Errorf("0x%x: must be registered as non-cryptographic", code)
+		}
+	}
+}
@@ -29,3 +29,16 @@ func GetHasher(indicator uint64) (hash.Hash, error) {
 
 // DefaultLengths maps a multihash indicator code to the output size for that hash, in units of bytes.
 var DefaultLengths = mhreg.DefaultLengths
+
+// Strength is an alias for Strength in the core package.
+type Strength = mhreg.Strength
+
+// RegisterStrength is an alias for RegisterStrength in the core package.
+func RegisterStrength(indicator uint64, s Strength) {
+	mhreg.RegisterStrength(indicator, s)
+}
+
+// GetStrength is an alias for GetStrength in the core package.
+func GetStrength(indicator uint64) (Strength, bool) {
+	return mhreg.GetStrength(indicator)
+}
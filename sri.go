@@ -0,0 +1,169 @@
+package multihash
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidSRI is returned when an integrity string or a multihash cannot be
+// represented as Subresource Integrity metadata.
+var ErrInvalidSRI = errors.New("invalid subresource integrity metadata")
+
+// sriAlgorithms lists the hash algorithms supported by the Subresource
+// Integrity spec, from weakest to strongest.
+var sriAlgorithms = []sriAlgorithm{
+	{"sha256", SHA2_256},
+	{"sha384", SHA2_384},
+	{"sha512", SHA2_512},
+}
+
+type sriAlgorithm struct {
+	name string
+	code uint64
+}
+
+// sriIndex returns the index of the named algorithm in sriAlgorithms, or -1.
+func sriIndex(name string) int {
+	for i, a := range sriAlgorithms {
+		if a.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FromSRI parses a Subresource Integrity string, such as the value of an HTML
+// integrity attribute, into multihashes. The string may hold several
+// whitespace-separated "alg-base64" entries; options following a "?" are
+// ignored.
+//
+// As required by the SRI spec, entries that are malformed or use algorithms
+// other than sha256, sha384 and sha512 are skipped rather than failing the
+// whole string, so the result may be empty. Digests may be base64 or
+// base64url, padded or not.
+func FromSRI(integrity string) ([]Multihash, error) {
+	var out []Multihash
+	for _, e := range parseSRI(integrity) {
+		if e.digest == nil {
+			continue
+		}
+		m, err := Encode(e.digest, sriAlgorithms[e.alg].code)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// sriEntry is an entry of an integrity string using a supported algorithm.
+type sriEntry struct {
+	// alg indexes sriAlgorithms.
+	alg int
+	// digest is nil if the value is not a digest of the right length.
+	digest []byte
+}
+
+// parseSRI returns the entries of an integrity string which use a supported
+// algorithm. As in the spec's "parse metadata" algorithm, entries with a
+// malformed value are kept: they still count when choosing the strongest
+// algorithm, and match nothing.
+func parseSRI(integrity string) []sriEntry {
+	var out []sriEntry
+	for _, token := range strings.Fields(integrity) {
+		token, _, _ = strings.Cut(token, "?")
+		alg, value, ok := strings.Cut(token, "-")
+		if !ok {
+			continue
+		}
+		i := sriIndex(alg)
+		if i < 0 {
+			continue
+		}
+		e := sriEntry{alg: i}
+		if digest, ok := decodeSRIValue(value); ok && len(digest) == DefaultLengths[sriAlgorithms[i].code] {
+			e.digest = digest
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// decodeSRIValue decodes either base64 or base64url, as allowed by the spec,
+// with or without padding.
+func decodeSRIValue(s string) ([]byte, bool) {
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding,
+		base64.RawStdEncoding,
+		base64.URLEncoding,
+		base64.RawURLEncoding,
+	} {
+		if b, err := enc.DecodeString(s); err == nil {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// SRI returns the Subresource Integrity form of the multihash, e.g.
+// "sha384-H8BRh8j4…".
+//
+// Only untruncated sha2-256, sha2-384 and sha2-512 multihashes can be
+// represented.
+func (m Multihash) SRI() (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range sriAlgorithms {
+		if a.code != dm.Code {
+			continue
+		}
+		if dm.Length != DefaultLengths[dm.Code] {
+			return "", fmt.Errorf("%w: %s multihash is truncated to %d bytes", ErrInvalidSRI, dm.Name, dm.Length)
+		}
+		return a.name + "-" + base64.StdEncoding.EncodeToString(dm.Digest), nil
+	}
+	return "", fmt.Errorf("%w: %s is not an SRI hash algorithm", ErrInvalidSRI, dm.Name)
+}
+
+// VerifySRI checks data against a Subresource Integrity string.
+//
+// Following the SRI spec, only the entries using the strongest algorithm
+// present are considered, and data matches if any of them match. An entry
+// whose value cannot be decoded still selects its algorithm, but never
+// matches. A mismatch is reported with an error matching ErrDigestMismatch.
+//
+// Note that, as in the spec, an integrity string with no entries of a
+// supported algorithm imposes no constraint and verifies successfully.
+func VerifySRI(data []byte, integrity string) error {
+	entries := parseSRI(integrity)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	strongest := 0
+	for _, e := range entries {
+		strongest = max(strongest, e.alg)
+	}
+	alg := sriAlgorithms[strongest]
+
+	actual, err := Sum(data, alg.code, -1)
+	if err != nil {
+		return err
+	}
+	dm, err := decode(actual)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.alg == strongest && bytes.Equal(e.digest, dm.Digest) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no %s entry matches", ErrDigestMismatch, alg.name)
+}
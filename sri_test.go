@@ -0,0 +1,141 @@
+package multihash
+
+import (
+	"errors"
+	"testing"
+)
+
+// Examples from the Subresource Integrity spec.
+var sriData = []byte("alert('Hello, world.');")
+
+const (
+	sriSHA256 = "sha256-qznLcsROx4GACP2dm0UCKCzCG+HiZ1guq6ZZDob/Tng="
+	sriSHA384 = "sha384-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO"
+	sriSHA512 = "sha512-Q2bFTOhEALkN8hOms2FKTDLy7eugP2zFZ1T8LCvX42Fp3WoNr3bjZSAHeOsHrbV1Fu9/A0EzCinRE7Af1ofPrw=="
+)
+
+func TestSRIRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		code uint64
+		sri  string
+	}{
+		{SHA2_256, sriSHA256},
+		{SHA2_384, sriSHA384},
+		{SHA2_512, sriSHA512},
+	} {
+		mhs, err := FromSRI(tc.sri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mhs) != 1 {
+			t.Fatalf("expected a single multihash, got %d", len(mhs))
+		}
+
+		expected, err := Sum(sriData, tc.code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mhs[0].HexString() != expected.HexString() {
+			t.Errorf("expected %s, got %s", expected, mhs[0])
+		}
+
+		s, err := mhs[0].SRI()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != tc.sri {
+			t.Errorf("expected %q, got %q", tc.sri, s)
+		}
+	}
+}
+
+func TestFromSRI(t *testing.T) {
+	mhs, err := FromSRI("  md5-deadbeef " + sriSHA256 + "?foo=bar\t" + sriSHA384 + "\nsha1-whatever ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 2 {
+		t.Fatalf("expected 2 multihashes, got %d", len(mhs))
+	}
+
+	// Malformed entries are skipped, and the others are still used.
+	mhs, err = FromSRI("sha384-notbase64! " + sriSHA384 + " sha256-H8BRh8j48O9oYatfu5AZzq6A9RINhZO5H16dQZngK7T62em8MUt1FLm52t+eX6xO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mhs) != 1 {
+		t.Fatalf("expected 1 multihash, got %d", len(mhs))
+	}
+	if s, _ := mhs[0].SRI(); s != sriSHA384 {
+		t.Errorf("expected %q, got %q", sriSHA384, s)
+	}
+
+	// base64url and unpadded digests are accepted.
+	for _, s := range []string{
+		"sha256-qznLcsROx4GACP2dm0UCKCzCG+HiZ1guq6ZZDob/Tng",
+		"sha256-qznLcsROx4GACP2dm0UCKCzCG-HiZ1guq6ZZDob_Tng=",
+		"sha256-qznLcsROx4GACP2dm0UCKCzCG-HiZ1guq6ZZDob_Tng",
+	} {
+		mhs, err := FromSRI(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mhs) != 1 {
+			t.Fatalf("%q: expected 1 multihash, got %d", s, len(mhs))
+		}
+		if sri, _ := mhs[0].SRI(); sri != sriSHA256 {
+			t.Errorf("%q: expected %q, got %q", s, sriSHA256, sri)
+		}
+	}
+}
+
+func TestSRIErrors(t *testing.T) {
+	trunc, err := Sum(sriData, SHA2_256, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := trunc.SRI(); !errors.Is(err, ErrInvalidSRI) {
+		t.Errorf("expected ErrInvalidSRI for a truncated multihash, got %v", err)
+	}
+
+	sha1, err := Sum(sriData, SHA1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sha1.SRI(); !errors.Is(err, ErrInvalidSRI) {
+		t.Errorf("expected ErrInvalidSRI for sha1, got %v", err)
+	}
+}
+
+func TestVerifySRI(t *testing.T) {
+	bogus384 := "sha384-dOTZf16X8p34q2/kYyEFm0jh89uTjikhnzjeLeF0FHsEaYKb1A1cv+Lyv4Hk8vHd"
+
+	for _, tc := range []struct {
+		integrity string
+		err       error
+	}{
+		{sriSHA256, nil},
+		{sriSHA384, nil},
+		{sriSHA512 + " " + sriSHA256, nil},
+		{"", nil},
+		{"md5-deadbeef", nil},
+		// The strongest algorithm wins, even though a weaker one would match.
+		{sriSHA256 + " " + bogus384, ErrDigestMismatch},
+		// Any entry of the strongest algorithm may match.
+		{bogus384 + " " + sriSHA384, nil},
+		// Malformed entries of a supported algorithm match nothing, but still
+		// select their algorithm, so they cannot downgrade the check.
+		{"sha256-!", ErrDigestMismatch},
+		{"sha384-AAAA", ErrDigestMismatch},
+		{"sha512-bad " + sriSHA256, ErrDigestMismatch},
+		{"sha512-! " + bogus384, ErrDigestMismatch},
+		{"sha512-! " + sriSHA512, nil},
+	} {
+		err := VerifySRI(sriData, tc.integrity)
+		if tc.err == nil && err != nil {
+			t.Errorf("%q: unexpected error %v", tc.integrity, err)
+		} else if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Errorf("%q: expected %v, got %v", tc.integrity, tc.err, err)
+		}
+	}
+}
@@ -32,6 +32,7 @@ var sumTestCases = []SumTestCase{
 	{multihash.SHA2_256, 31, "foo", "121f2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7", nil},
 	{multihash.SHA2_256, 32, "foo", "12202c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", nil},
 	{multihash.SHA2_256, 16, "foo", "12102c26b46b68ffc68ff99b453c1d304134", nil},
+	{multihash.SHA2_384, -1, "foo", "203098c11ffdfdd540676b1a137cb1a22b2a70350c9a44171d6b1180c6be5cbb2ee3f79d532c8a1dd9ef2e8e08e752a3babb", nil},
 	{multihash.SHA2_512, -1, "foo", "1340f7fbba6e0636f890e56fbbf3283e524c6fa3204ae298382d624741d0dc6638326e282c41be5e4254d8820772c5518a2c5a8c0c7f7eda19594a7eb539453e1ed7", nil},
 	{multihash.SHA2_512, 32, "foo", "1320f7fbba6e0636f890e56fbbf3283e524c6fa3204ae298382d624741d0dc663832", nil},
 	{multihash.SHA3, 32, "foo", "14204bca2b137edc580fe50a88983ef860ebaca36c857b1f492839d6d7392452a63c", nil},
@@ -57,16 +58,21 @@ var sumTestCases = []SumTestCase{
 	{multihash.BLAKE2B_MIN, -1, "foo", "81e4020152", nil},
 	{multihash.BLAKE2B_MIN, 1, "foo", "81e4020152", nil},
 	{multihash.BLAKE2S_MAX, 32, "foo", "e0e4022008d6cad88075de8f192db097573d0e829411cd91eb6ec65e8fc16c017edfdb74", nil},
+	{multihash.KECCAK_224, -1, "foo", "1a1cdaa94da7f6806bf5a4e0af60379d75c62cadd6be5427c16d01e76cca", nil},
 	{multihash.KECCAK_256, 32, "foo", "1b2041b1a0649752af1b28b3dc29a1556eee781e4a4c3a1f7f53f90fa834de098c4d", nil},
+	{multihash.KECCAK_384, -1, "foo", "1c3019d3f8607d2c6519443ab70bf1f7c86e9da4fda7fbcba7bfae0cab6190d24606f48334a7382c60db479d49bfd9fa815c", nil},
 	{multihash.KECCAK_512, -1, "beep boop", "1d40e161c54798f78eba3404ac5e7e12d27555b7b810e7fd0db3f25ffa0c785c438331b0fbb6156215f69edf403c642e5280f4521da9bd767296ec81f05100852e78", nil},
 	{multihash.SHAKE_128, 32, "foo", "1820f84e95cb5fbd2038863ab27d3cdeac295ad2d4ab96ad1f4b070c0bf36078ef08", nil},
 	{multihash.SHAKE_256, 64, "foo", "19401af97f7818a28edfdfce5ec66dbdc7e871813816d7d585fe1f12475ded5b6502b7723b74e2ee36f2651a10a8eaca72aa9148c3c761aaceac8f6d6cc64381ed39", nil},
 	{multihash.MD5, -1, "foo", "d50110acbd18db4cc2f85cedef654fccc4a4d8", nil},
+	{multihash.RIPEMD_160, -1, "foo", "d3201442cfa211018ea492fdee45ac637b7972a0ad6873", nil},
+	{multihash.SM3_256, -1, "foo", "cda6012098474f4c313766eb66422d94760567eb9e36ee970827c96cfd7c6cd77253eef0", nil},
 	{multihash.BLAKE3, 32, "foo", "1e2004e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9", nil},
 	{multihash.BLAKE3, 64, "foo", "1e4004e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9791074b7511b59d31c71c62f5a745689fa6c9497f68bdf1061fe07f518d410c0", nil},
 	{multihash.BLAKE3, 128, "foo", "1e800104e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9791074b7511b59d31c71c62f5a745689fa6c9497f68bdf1061fe07f518d410c0b0c27f41b3cf083f8a7fdc67a877e21790515762a754a45dcb8a356722698a7af5ed2bb608983d5aa75d4d61691ef132efe8631ce0afc15553a08fffc60ee936", nil},
 	{multihash.BLAKE3, -1, "foo", "1e2004e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9", nil},
 	{multihash.BLAKE3, 129, "foo", "1e810104e0bb39f30b1a3feb89f536c93be15055482df748674b00d26e5a75777702e9791074b7511b59d31c71c62f5a745689fa6c9497f68bdf1061fe07f518d410c0b0c27f41b3cf083f8a7fdc67a877e21790515762a754a45dcb8a356722698a7af5ed2bb608983d5aa75d4d61691ef132efe8631ce0afc15553a08fffc60ee9369b", multihash.ErrLenTooLarge},
+	{multihash.KANGAROOTWELVE, -1, "foo", "813a201c5bd05192a090c1208a898fda0191990cc69c560e2e598b3a2a8342e17495df", nil},
 }
 
 func TestSum(t *testing.T) {
@@ -0,0 +1,333 @@
+package multihash
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTorrent is returned for malformed infohashes, magnet URIs and
+// torrent metainfo.
+var ErrInvalidTorrent = errors.New("invalid bittorrent data")
+
+// Sizes of BitTorrent infohashes, in bytes.
+const (
+	// InfohashV1Size is the size of a v1 (sha1) infohash, which is also the
+	// size a v2 infohash is truncated to where only 20 bytes fit, such as on
+	// the DHT and trackers.
+	InfohashV1Size = 20
+	// InfohashV2Size is the size of a v2 (sha2-256) infohash.
+	InfohashV2Size = 32
+)
+
+// SumTorrentInfo computes the infohashes of the bencoded info dictionary of
+// a torrent, such as returned by TorrentInfo: the v1 (sha1) infohash and the
+// v2 (BEP 52, sha2-256) infohash. Only those matching the torrent's version
+// are meaningful; hybrid torrents use both.
+func SumTorrentInfo(bencodedInfo []byte) (v1, v2 Multihash, err error) {
+	end, err := bencodeValue(bencodedInfo, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if end != len(bencodedInfo) || bencodedInfo[0] != 'd' {
+		return nil, nil, fmt.Errorf("%w: info must be a single bencoded dictionary", ErrInvalidTorrent)
+	}
+	if v1, err = Sum(bencodedInfo, SHA1, -1); err != nil {
+		return nil, nil, err
+	}
+	if v2, err = Sum(bencodedInfo, SHA2_256, -1); err != nil {
+		return nil, nil, err
+	}
+	return v1, v2, nil
+}
+
+// TorrentInfo returns the raw bencoded info dictionary of a .torrent file,
+// exactly as it appears in the file, which is what infohashes are computed
+// over.
+func TorrentInfo(torrent []byte) ([]byte, error) {
+	if len(torrent) == 0 || torrent[0] != 'd' {
+		return nil, fmt.Errorf("%w: metainfo is not a dictionary", ErrInvalidTorrent)
+	}
+	var info []byte
+	i := 1
+	for i < len(torrent) && torrent[i] != 'e' {
+		keyEnd, err := bencodeValue(torrent, i)
+		if err != nil {
+			return nil, err
+		}
+		if torrent[i] < '0' || torrent[i] > '9' {
+			return nil, fmt.Errorf("%w: dictionary key is not a string", ErrInvalidTorrent)
+		}
+		key := torrent[i:keyEnd]
+		valueEnd, err := bencodeValue(torrent, keyEnd)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(key, []byte("4:info")) {
+			info = torrent[keyEnd:valueEnd]
+		}
+		i = valueEnd
+	}
+	if i >= len(torrent) || i+1 != len(torrent) {
+		return nil, fmt.Errorf("%w: malformed metainfo dictionary", ErrInvalidTorrent)
+	}
+	if info == nil || info[0] != 'd' {
+		return nil, fmt.Errorf("%w: metainfo has no info dictionary", ErrInvalidTorrent)
+	}
+	return info, nil
+}
+
+// bencodeValue returns the end offset of the bencoded value starting at i.
+func bencodeValue(b []byte, i int) (int, error) {
+	if i >= len(b) {
+		return 0, fmt.Errorf("%w: unexpected end of bencoded data", ErrInvalidTorrent)
+	}
+	switch c := b[i]; {
+	case c == 'i':
+		end := bytes.IndexByte(b[i:], 'e')
+		if end < 0 {
+			return 0, fmt.Errorf("%w: unterminated integer", ErrInvalidTorrent)
+		}
+		if _, err := strconv.ParseInt(string(b[i+1:i+end]), 10, 64); err != nil {
+			return 0, fmt.Errorf("%w: bad integer at offset %d", ErrInvalidTorrent, i)
+		}
+		return i + end + 1, nil
+	case c >= '0' && c <= '9':
+		colon := bytes.IndexByte(b[i:], ':')
+		if colon < 0 {
+			return 0, fmt.Errorf("%w: unterminated string length", ErrInvalidTorrent)
+		}
+		n, err := strconv.Atoi(string(b[i : i+colon]))
+		if err != nil || n > len(b)-(i+colon+1) {
+			return 0, fmt.Errorf("%w: bad string length at offset %d", ErrInvalidTorrent, i)
+		}
+		return i + colon + 1 + n, nil
+	case c == 'l' || c == 'd':
+		i++
+		for n := 0; ; n++ {
+			if i >= len(b) {
+				return 0, fmt.Errorf("%w: unterminated list or dictionary", ErrInvalidTorrent)
+			}
+			if b[i] == 'e' {
+				if c == 'd' && n%2 != 0 {
+					return 0, fmt.Errorf("%w: dictionary key without value", ErrInvalidTorrent)
+				}
+				return i + 1, nil
+			}
+			if c == 'd' && n%2 == 0 && (b[i] < '0' || b[i] > '9') {
+				return 0, fmt.Errorf("%w: dictionary key is not a string", ErrInvalidTorrent)
+			}
+			end, err := bencodeValue(b, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		}
+	default:
+		return 0, fmt.Errorf("%w: unexpected %q at offset %d", ErrInvalidTorrent, c, i)
+	}
+}
+
+// FromBTIH parses a v1 infohash, as found in "urn:btih:" magnet links, in
+// either its 40 character hex or 32 character base32 form.
+func FromBTIH(s string) (Multihash, error) {
+	var b []byte
+	var err error
+	switch len(s) {
+	case hex.EncodedLen(InfohashV1Size):
+		b, err = hex.DecodeString(s)
+	case base32.StdEncoding.EncodedLen(InfohashV1Size):
+		b, err = base32.StdEncoding.DecodeString(strings.ToUpper(s))
+	default:
+		return nil, fmt.Errorf("%w: infohash %q must be 40 hex or 32 base32 characters", ErrInvalidTorrent, s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTorrent, err)
+	}
+	return Encode(b, SHA1)
+}
+
+// BTIH returns the hex form of a 20 byte infohash: either a v1 (sha1)
+// infohash, or a v2 (sha2-256) infohash truncated with TruncateInfohash.
+func (m Multihash) BTIH() (string, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return "", err
+	}
+	if (dm.Code != SHA1 && dm.Code != SHA2_256) || dm.Length != InfohashV1Size {
+		return "", fmt.Errorf("%w: %s multihash of %d bytes is not a 20 byte infohash", ErrInvalidTorrent, dm.Name, dm.Length)
+	}
+	return hex.EncodeToString(dm.Digest), nil
+}
+
+// TruncateInfohash truncates a v2 infohash to the 20 bytes used in v1
+// contexts, such as the DHT and trackers, keeping the sha2-256 code.
+func TruncateInfohash(m Multihash) (Multihash, error) {
+	dm, err := decode(m)
+	if err != nil {
+		return nil, err
+	}
+	if dm.Code != SHA2_256 || dm.Length < InfohashV1Size {
+		return nil, fmt.Errorf("%w: %s multihash of %d bytes is not a v2 infohash", ErrInvalidTorrent, dm.Name, dm.Length)
+	}
+	return Encode(dm.Digest[:InfohashV1Size], SHA2_256)
+}
+
+// Magnet is a BitTorrent magnet link.
+type Magnet struct {
+	// InfoHashes holds the exact topics of the link: sha1 multihashes for
+	// "urn:btih:" v1 infohashes and sha2-256 multihashes for "urn:btmh:" v2
+	// infohashes.
+	InfoHashes []Multihash
+	// DisplayName is the "dn" parameter.
+	DisplayName string
+	// Trackers are the "tr" parameters.
+	Trackers []string
+	// Params holds the remaining parameters.
+	Params url.Values
+}
+
+// ParseMagnet parses a "magnet:?" URI. Exact topics other than "urn:btih:"
+// and "urn:btmh:" are kept in Params.
+func ParseMagnet(uri string) (*Magnet, error) {
+	query, ok := strings.CutPrefix(uri, "magnet:?")
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a magnet URI", ErrInvalidTorrent, uri)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTorrent, err)
+	}
+
+	// Sort the keys so that numbered topics such as "xt.1", "xt.2" and
+	// "xt.10" keep their order.
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return magnetKeyLess(keys[i], keys[j]) })
+
+	mag := &Magnet{Params: url.Values{}}
+	for _, key := range keys {
+		base, _, _ := strings.Cut(key, ".")
+		for _, v := range values[key] {
+			switch base {
+			case "xt":
+				m, err := parseExactTopic(v)
+				if err != nil {
+					return nil, err
+				}
+				if m == nil {
+					mag.Params.Add(key, v)
+					continue
+				}
+				mag.InfoHashes = append(mag.InfoHashes, m)
+			case "dn":
+				mag.DisplayName = v
+			case "tr":
+				mag.Trackers = append(mag.Trackers, v)
+			default:
+				mag.Params.Add(key, v)
+			}
+		}
+	}
+	if len(mag.InfoHashes) == 0 {
+		return nil, fmt.Errorf("%w: magnet URI has no infohash", ErrInvalidTorrent)
+	}
+	return mag, nil
+}
+
+// magnetKeyLess orders magnet parameters by name, then by their ".N" suffix:
+// a parameter without a suffix comes first, and numeric suffixes are
+// compared as numbers.
+func magnetKeyLess(a, b string) bool {
+	baseA, suffixA, okA := strings.Cut(a, ".")
+	baseB, suffixB, okB := strings.Cut(b, ".")
+	if baseA != baseB {
+		return baseA < baseB
+	}
+	if okA != okB {
+		return !okA
+	}
+	nA, errA := strconv.ParseUint(suffixA, 10, 64)
+	nB, errB := strconv.ParseUint(suffixB, 10, 64)
+	switch {
+	case errA == nil && errB == nil && nA != nB:
+		return nA < nB
+	case (errA == nil) != (errB == nil):
+		return errA == nil
+	}
+	return suffixA < suffixB
+}
+
+// parseExactTopic returns nil for topics which are not infohashes.
+func parseExactTopic(v string) (Multihash, error) {
+	switch {
+	case strings.HasPrefix(v, "urn:btih:"):
+		return FromBTIH(strings.TrimPrefix(v, "urn:btih:"))
+	case strings.HasPrefix(v, "urn:btmh:"):
+		m, err := FromHexString(strings.TrimPrefix(v, "urn:btmh:"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidTorrent, err)
+		}
+		if dm, _ := decode(m); dm.Code != SHA2_256 || dm.Length != InfohashV2Size {
+			return nil, fmt.Errorf("%w: urn:btmh must hold a sha2-256 multihash", ErrInvalidTorrent)
+		}
+		return m, nil
+	default:
+		return nil, nil
+	}
+}
+
+// URI returns the magnet URI. 20 byte infohashes, whether v1 (sha1) or v2
+// truncated with TruncateInfohash, are written as "urn:btih:" exact topics
+// and parse back as sha1 multihashes; full v2 infohashes are written as
+// "urn:btmh:". Any other multihash is an error.
+func (mag *Magnet) URI() (string, error) {
+	var b strings.Builder
+	b.WriteString("magnet:?")
+	sep := func() {
+		if b.Len() > len("magnet:?") {
+			b.WriteByte('&')
+		}
+	}
+
+	for _, m := range mag.InfoHashes {
+		dm, err := decode(m)
+		if err != nil {
+			return "", err
+		}
+		sep()
+		switch {
+		case dm.Length == InfohashV1Size:
+			btih, err := m.BTIH()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString("xt=urn:btih:" + btih)
+		case dm.Code == SHA2_256 && dm.Length == InfohashV2Size:
+			b.WriteString("xt=urn:btmh:" + m.HexString())
+		default:
+			return "", fmt.Errorf("%w: %s multihash of %d bytes is not an infohash", ErrInvalidTorrent, dm.Name, dm.Length)
+		}
+	}
+	if mag.DisplayName != "" {
+		sep()
+		b.WriteString("dn=" + url.QueryEscape(mag.DisplayName))
+	}
+	for _, tr := range mag.Trackers {
+		sep()
+		b.WriteString("tr=" + url.QueryEscape(tr))
+	}
+	if len(mag.Params) > 0 {
+		sep()
+		b.WriteString(mag.Params.Encode())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,238 @@
+package multihash
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testTorrent returns a single file .torrent holding "hello world\n".
+func testTorrent() (torrent, info []byte) {
+	piece := sha1.Sum([]byte("hello world\n"))
+	info = append([]byte("d6:lengthi12e4:name9:hello.txt12:piece lengthi16384e6:pieces20:"), piece[:]...)
+	info = append(info, 'e')
+
+	torrent = []byte("d8:announce31:http://tracker.example/announce13:creation datei1700000000e4:info")
+	torrent = append(torrent, info...)
+	torrent = append(torrent, 'e')
+	return torrent, info
+}
+
+func TestSumTorrentInfo(t *testing.T) {
+	torrent, expected := testTorrent()
+	info, err := TorrentInfo(torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(info, expected) {
+		t.Fatalf("extracted info %q, expected %q", info, expected)
+	}
+
+	v1, v2, err := SumTorrentInfo(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(v1[2:]) != "7b5e918f364908afab937ecdd84059dfb61102b7" {
+		t.Errorf("unexpected v1 infohash %s", v1.HexString())
+	}
+	if hex.EncodeToString(v2[2:]) != "17c415ee3c1417dbacb7522b9de2a70f4952897231508436b75df68ae11514c5" {
+		t.Errorf("unexpected v2 infohash %s", v2.HexString())
+	}
+
+	for _, bad := range []string{
+		"",
+		"de",
+		"d4:infoi1ee",
+		"d4:infod1:ai1ee",
+		"di1e4:infod1:ai1eee",
+		"d4:infod1:ai1ex",
+		"d4:infod5:a1:b",
+	} {
+		if _, err := TorrentInfo([]byte(bad)); !errors.Is(err, ErrInvalidTorrent) {
+			t.Errorf("%q: expected ErrInvalidTorrent, got %v", bad, err)
+		}
+	}
+	if _, _, err := SumTorrentInfo([]byte("d1:ai1eel")); !errors.Is(err, ErrInvalidTorrent) {
+		t.Errorf("expected ErrInvalidTorrent for trailing data, got %v", err)
+	}
+}
+
+func TestBTIH(t *testing.T) {
+	const (
+		hexHash = "7b5e918f364908afab937ecdd84059dfb61102b7"
+		b32Hash = "PNPJDDZWJEEK7K4TP3G5QQCZ363BCAVX"
+	)
+	for _, s := range []string{hexHash, "7B5E918F364908AFAB937ECDD84059DFB61102B7", b32Hash, "pnpjddzwjeek7k4tp3g5qqcz363bcavx"} {
+		m, err := FromBTIH(s)
+		if err != nil {
+			t.Fatalf("%q: %s", s, err)
+		}
+		if m.HexString() != "1114"+hexHash {
+			t.Errorf("%q: unexpected multihash %s", s, m.HexString())
+		}
+		s, err := m.BTIH()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != hexHash {
+			t.Errorf("expected %q, got %q", hexHash, s)
+		}
+	}
+
+	for _, s := range []string{"", "7b5e918f", hexHash + "00", "zz5e918f364908afab937ecdd84059dfb61102b7", "PNPJDDZWJEEK7K4TP3G5QQCZ363BCAV1"} {
+		if _, err := FromBTIH(s); !errors.Is(err, ErrInvalidTorrent) {
+			t.Errorf("%q: expected ErrInvalidTorrent, got %v", s, err)
+		}
+	}
+}
+
+func TestTruncateInfohash(t *testing.T) {
+	_, info := testTorrent()
+	v1, v2, err := SumTorrentInfo(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v2.BTIH(); !errors.Is(err, ErrInvalidTorrent) {
+		t.Errorf("expected ErrInvalidTorrent for an untruncated v2 infohash, got %v", err)
+	}
+	trunc, err := TruncateInfohash(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := trunc.BTIH()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "17c415ee3c1417dbacb7522b9de2a70f49528972" {
+		t.Errorf("unexpected truncated infohash %q", s)
+	}
+
+	if _, err := TruncateInfohash(v1); !errors.Is(err, ErrInvalidTorrent) {
+		t.Errorf("expected ErrInvalidTorrent for a v1 infohash, got %v", err)
+	}
+}
+
+func TestMagnet(t *testing.T) {
+	const uri = "magnet:?xt=urn:btih:631a31dd0a46257d5078c0dee4e66e26f73e42ac" +
+		"&xt=urn:btmh:1220d8dd32ac93357c368556af3ac1d95c9d76bd0dff6fa9833ecdac3d53134efabb" +
+		"&dn=bittorrent-v1-v2-hybrid-test&tr=udp%3A%2F%2Ftracker.example%3A6969&x.pe=10.0.0.1%3A6881"
+
+	mag, err := ParseMagnet(uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mag.InfoHashes) != 2 {
+		t.Fatalf("expected 2 infohashes, got %d", len(mag.InfoHashes))
+	}
+	if mag.InfoHashes[0].HexString() != "1114631a31dd0a46257d5078c0dee4e66e26f73e42ac" {
+		t.Errorf("unexpected v1 infohash %s", mag.InfoHashes[0].HexString())
+	}
+	if mag.InfoHashes[1].HexString() != "1220d8dd32ac93357c368556af3ac1d95c9d76bd0dff6fa9833ecdac3d53134efabb" {
+		t.Errorf("unexpected v2 infohash %s", mag.InfoHashes[1].HexString())
+	}
+	if mag.DisplayName != "bittorrent-v1-v2-hybrid-test" {
+		t.Errorf("unexpected display name %q", mag.DisplayName)
+	}
+	if len(mag.Trackers) != 1 || mag.Trackers[0] != "udp://tracker.example:6969" {
+		t.Errorf("unexpected trackers %q", mag.Trackers)
+	}
+	if mag.Params.Get("x.pe") != "10.0.0.1:6881" {
+		t.Errorf("unexpected params %v", mag.Params)
+	}
+	if s, err := mag.URI(); err != nil || s != uri {
+		t.Errorf("round trip:\nexpected %s\n     got %s (%v)", uri, s, err)
+	}
+
+	numbered, err := ParseMagnet("magnet:?xt.2=urn:btmh:1220d8dd32ac93357c368556af3ac1d95c9d76bd0dff6fa9833ecdac3d53134efabb&xt.1=urn:btih:MMNDDXIKIYSX2UDYYDPOJZTOE33T4QVM")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(numbered.InfoHashes) != 2 || !bytes.Equal(numbered.InfoHashes[0], mag.InfoHashes[0]) || !bytes.Equal(numbered.InfoHashes[1], mag.InfoHashes[1]) {
+		t.Errorf("numbered topics parsed out of order")
+	}
+
+	// Topic numbers are compared as numbers, so xt.10 follows xt.9.
+	var query []string
+	for i := 11; i >= 1; i-- {
+		query = append(query, fmt.Sprintf("xt.%d=urn:btih:%040x", i, i))
+	}
+	many, err := ParseMagnet("magnet:?" + strings.Join(query, "&"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range many.InfoHashes {
+		if dm, _ := Decode(m); dm.Digest[len(dm.Digest)-1] != byte(i+1) {
+			t.Errorf("infohash %d is topic xt.%d", i, dm.Digest[len(dm.Digest)-1])
+		}
+	}
+
+	for _, bad := range []string{
+		"http://example.com/",
+		"magnet:?dn=nothing",
+		"magnet:?xt=urn:btih:631a31dd",
+		"magnet:?xt=urn:btmh:1114631a31dd0a46257d5078c0dee4e66e26f73e42ac",
+		"magnet:?xt=urn:btmh:12",
+	} {
+		if _, err := ParseMagnet(bad); !errors.Is(err, ErrInvalidTorrent) {
+			t.Errorf("%q: expected ErrInvalidTorrent, got %v", bad, err)
+		}
+	}
+}
+
+func TestMagnetURI(t *testing.T) {
+	_, info := testTorrent()
+	v1, v2, err := SumTorrentInfo(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated, err := TruncateInfohash(v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A truncated v2 infohash is written, and parsed back, as a v1 one.
+	mag := &Magnet{InfoHashes: []Multihash{v1, v2, truncated}, DisplayName: "test"}
+	uri, err := mag.URI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseMagnet(uri)
+	if err != nil {
+		t.Fatalf("%s: %s", uri, err)
+	}
+	btih, _ := truncated.BTIH()
+	asV1, err := FromBTIH(btih)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []Multihash{v1, v2, asV1} {
+		if i >= len(parsed.InfoHashes) || !bytes.Equal(parsed.InfoHashes[i], expected) {
+			t.Errorf("%s: infohash %d: expected %s", uri, i, expected.HexString())
+		}
+	}
+	if again, err := parsed.URI(); err != nil || again != uri {
+		t.Errorf("round trip:\nexpected %s\n     got %s (%v)", uri, again, err)
+	}
+
+	for _, code := range []uint64{MD5, SHA2_512} {
+		m, err := Sum(info, code, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := (&Magnet{InfoHashes: []Multihash{m}}).URI(); !errors.Is(err, ErrInvalidTorrent) {
+			t.Errorf("0x%x: expected ErrInvalidTorrent, got %v", code, err)
+		}
+	}
+	short, err := Sum(info, SHA2_256, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Magnet{InfoHashes: []Multihash{short}}).URI(); !errors.Is(err, ErrInvalidTorrent) {
+		t.Errorf("16 byte sha2-256: expected ErrInvalidTorrent, got %v", err)
+	}
+}
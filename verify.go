@@ -0,0 +1,163 @@
+package multihash
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ErrMismatch is returned by the Verify and VerifyStream methods when data
+// does not hash to the expected multihash. It matches ErrDigestMismatch with
+// errors.Is.
+type ErrMismatch struct {
+	Expected DecodedMultihash
+	// Actual is the digest of the data, truncated like the expected one.
+	Actual []byte
+}
+
+func (e ErrMismatch) Error() string {
+	return fmt.Sprintf("%s: %s expected %x, got %x", ErrDigestMismatch, e.Expected.Name, e.Expected.Digest, e.Actual)
+}
+
+func (e ErrMismatch) Is(target error) bool {
+	return target == ErrDigestMismatch
+}
+
+// Verify checks that data hashes to m, using the hash function and the
+// (possibly truncated) length recorded in m. A mismatch is reported as an
+// ErrMismatch.
+func (m Multihash) Verify(data []byte) error {
+	dm, err := decode(m)
+	if err != nil {
+		return err
+	}
+	return dm.Verify(data)
+}
+
+// VerifyStream is like Verify for data read from r.
+func (m Multihash) VerifyStream(r io.Reader) error {
+	dm, err := decode(m)
+	if err != nil {
+		return err
+	}
+	return dm.VerifyStream(r)
+}
+
+// Verify checks that data hashes to dm, using its hash function and
+// (possibly truncated) length. A mismatch is reported as an ErrMismatch.
+//
+// For an identity multihash, the data must equal the digest.
+func (dm DecodedMultihash) Verify(data []byte) error {
+	if dm.Code == IDENTITY {
+		return dm.check(data)
+	}
+	actual, err := Sum(data, dm.Code, dm.Length)
+	if err != nil {
+		return err
+	}
+	return dm.checkMultihash(actual)
+}
+
+// VerifyStream is like Verify for data read from r. All of r is read, except
+// for identity multihashes, where reading stops once r is known to be longer
+// than the digest.
+func (dm DecodedMultihash) VerifyStream(r io.Reader) error {
+	if dm.Code == IDENTITY {
+		data, err := io.ReadAll(io.LimitReader(r, int64(dm.Length)+1))
+		if err != nil {
+			return err
+		}
+		return dm.check(data)
+	}
+	actual, err := SumStream(r, dm.Code, dm.Length)
+	if err != nil {
+		return err
+	}
+	return dm.checkMultihash(actual)
+}
+
+func (dm DecodedMultihash) checkMultihash(actual Multihash) error {
+	adm, err := decode(actual)
+	if err != nil {
+		return err
+	}
+	return dm.check(adm.Digest)
+}
+
+func (dm DecodedMultihash) check(actual []byte) error {
+	if len(actual) != len(dm.Digest) || subtle.ConstantTimeCompare(actual, dm.Digest) != 1 {
+		return ErrMismatch{Expected: dm, Actual: actual}
+	}
+	return nil
+}
+
+// Encode returns the multihash of dm, the inverse of Decode. Length must
+// match the length of Digest.
+func (dm DecodedMultihash) Encode() (Multihash, error) {
+	if dm.Length != len(dm.Digest) {
+		return nil, ErrInconsistentLen{dm, len(dm.Digest)}
+	}
+	return Encode(dm.Digest, dm.Code)
+}
+
+// ErrNoUsableMultihash is returned by VerifyAny when none of the multihashes
+// can be used under the policy.
+var ErrNoUsableMultihash = errors.New("no usable multihash")
+
+// VerifyPolicy controls how VerifyAny uses several multihashes of the same
+// data.
+type VerifyPolicy struct {
+	// All verifies every usable multihash, instead of only the strongest.
+	All bool
+	// MinBits excludes multihashes whose effective strength, given their
+	// length, is below this many bits. See Strength.EffectiveBits.
+	MinBits int
+}
+
+// VerifyAny checks data against the strongest of mhs, ranked by the
+// registered Strength of each code and the length of each digest, and
+// returns the multihash it used. Multihashes without a registered hasher are
+// skipped; codes without a registered strength rank lowest.
+//
+// With policy.All, every usable multihash is checked and the first mismatch
+// is returned; the strongest is still reported as used.
+func VerifyAny(data []byte, mhs []Multihash, policy VerifyPolicy) (Multihash, error) {
+	type candidate struct {
+		m        Multihash
+		dm       DecodedMultihash
+		strength Strength
+	}
+	var usable []candidate
+	for _, m := range mhs {
+		dm, err := decode(m)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := GetHasher(dm.Code); err != nil {
+			continue
+		}
+		strength, _ := GetStrength(dm.Code)
+		if strength.EffectiveBits(dm.Length) < policy.MinBits {
+			continue
+		}
+		usable = append(usable, candidate{m, dm, strength})
+	}
+	if len(usable) == 0 {
+		return nil, fmt.Errorf("%w: none of %d multihashes is supported and strong enough", ErrNoUsableMultihash, len(mhs))
+	}
+
+	sort.SliceStable(usable, func(i, j int) bool {
+		return usable[j].strength.Less(usable[j].dm.Length, usable[i].strength, usable[i].dm.Length)
+	})
+	if !policy.All {
+		usable = usable[:1]
+	}
+	for _, c := range usable {
+		if err := c.dm.Verify(data); err != nil {
+			return nil, err
+		}
+	}
+	return usable[0].m, nil
+}
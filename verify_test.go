@@ -0,0 +1,213 @@
+package multihash
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	data := []byte("hello world")
+	for _, tc := range []struct {
+		code   uint64
+		length int
+	}{
+		{SHA1, -1},
+		{SHA2_256, -1},
+		{SHA2_256, 20},
+		{SHA2_512, 32},
+		{SHA3_256, -1},
+		{BLAKE2B_MIN + 31, -1},
+		{IDENTITY, -1},
+	} {
+		m, err := Sum(data, tc.code, tc.length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm, err := Decode(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := m.Verify(data); err != nil {
+			t.Errorf("%s/%d: Verify: %s", dm.Name, dm.Length, err)
+		}
+		if err := m.VerifyStream(bytes.NewReader(data)); err != nil {
+			t.Errorf("%s/%d: VerifyStream: %s", dm.Name, dm.Length, err)
+		}
+		if err := dm.Verify(data); err != nil {
+			t.Errorf("%s/%d: DecodedMultihash.Verify: %s", dm.Name, dm.Length, err)
+		}
+
+		for _, other := range [][]byte{[]byte("hello world!"), []byte("hello"), nil} {
+			err := m.Verify(other)
+			var mismatch ErrMismatch
+			if !errors.As(err, &mismatch) || !errors.Is(err, ErrDigestMismatch) {
+				t.Errorf("%s/%d: %q: expected ErrMismatch, got %v", dm.Name, dm.Length, other, err)
+				continue
+			}
+			if mismatch.Expected.Code != dm.Code {
+				t.Errorf("%s/%d: mismatch reports code 0x%x", dm.Name, dm.Length, mismatch.Expected.Code)
+			}
+			if err := dm.VerifyStream(bytes.NewReader(other)); !errors.Is(err, ErrDigestMismatch) {
+				t.Errorf("%s/%d: %q: expected ErrDigestMismatch from VerifyStream, got %v", dm.Name, dm.Length, other, err)
+			}
+		}
+	}
+}
+
+func TestVerifyIdentityStreamLimit(t *testing.T) {
+	m, err := Sum([]byte("abc"), IDENTITY, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := strings.NewReader("abcdefgh")
+	if err := m.VerifyStream(r); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected ErrDigestMismatch, got %v", err)
+	}
+	if r.Len() != 4 {
+		t.Errorf("expected reading to stop after 4 bytes, %d left", r.Len())
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	if err := Multihash([]byte{0x12, 0x20, 0x00}).Verify(nil); err == nil {
+		t.Error("expected an error for an invalid multihash")
+	}
+	if err := Multihash([]byte{0x11, 0x21}).Verify(nil); err == nil {
+		t.Error("expected an error for a digest longer than the hash function's")
+	}
+	m, err := Encode(make([]byte, 32), 0x9999)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Multihash(m).Verify(nil); !errors.Is(err, ErrSumNotSupported) {
+		t.Errorf("expected ErrSumNotSupported, got %v", err)
+	}
+}
+
+func TestDecodedMultihashEncode(t *testing.T) {
+	for _, tc := range testCases {
+		m, err := tc.Multihash()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dm, err := Decode(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m2, err := dm.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(m, m2) {
+			t.Errorf("%s: round trip gave %s", m.HexString(), m2.HexString())
+		}
+	}
+
+	dm := DecodedMultihash{Code: SHA1, Length: 20, Digest: make([]byte, 19)}
+	if _, err := dm.Encode(); !errors.As(err, &ErrInconsistentLen{}) {
+		t.Errorf("expected ErrInconsistentLen, got %v", err)
+	}
+}
+
+func TestVerifyAny(t *testing.T) {
+	data := []byte("hello world")
+	sum := func(code uint64, length int) Multihash {
+		m, err := Sum(data, code, length)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	md5 := sum(MD5, -1)
+	sha256 := sum(SHA2_256, -1)
+	sha512 := sum(SHA2_512, -1)
+	murmur := sum(MURMUR3X64_64, -1)
+	truncated := sum(SHA2_512, 16)
+	unsupported, err := Encode(make([]byte, 32), 0x9999)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	used, err := VerifyAny(data, []Multihash{murmur, md5, sha256, unsupported, truncated, sha512}, VerifyPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(used, sha512) {
+		t.Errorf("expected sha2-512 to be used, got %s", used.HexString())
+	}
+
+	// A truncated sha2-512 is weaker than a full sha2-256.
+	used, err = VerifyAny(data, []Multihash{truncated, sha256}, VerifyPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(used, sha256) {
+		t.Errorf("expected sha2-256 to be used, got %s", used.HexString())
+	}
+
+	// A broken cryptographic hash still beats a non-cryptographic one.
+	used, err = VerifyAny(data, []Multihash{murmur, md5}, VerifyPolicy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(used, md5) {
+		t.Errorf("expected md5 to be used, got %s", used.HexString())
+	}
+
+	// Only the strongest is checked unless All is set.
+	bad := sum(SHA2_256, -1)
+	bad[len(bad)-1] ^= 1
+	if _, err := VerifyAny(data, []Multihash{bad, sha512}, VerifyPolicy{}); err != nil {
+		t.Errorf("expected success ignoring the weaker digest, got %v", err)
+	}
+	if _, err := VerifyAny(data, []Multihash{bad, sha512}, VerifyPolicy{All: true}); !errors.Is(err, ErrDigestMismatch) {
+		t.Errorf("expected ErrDigestMismatch with All, got %v", err)
+	}
+
+	// Poseidon and commP zero-pad their input, so they must not be preferred
+	// over a hash which would catch the padding.
+	padded := append(data[:len(data):len(data)], 0)
+	for _, code := range []uint64{POSEIDON_BLS12_381_A2_FC1, SHA2_256_TRUNC254_PADDED} {
+		if _, err := VerifyAny(padded, []Multihash{md5, sum(code, -1)}, VerifyPolicy{}); !errors.Is(err, ErrDigestMismatch) {
+			t.Errorf("%s: expected md5 to catch the padding, got %v", Codes[code], err)
+		}
+	}
+
+	if _, err := VerifyAny(data, []Multihash{md5, murmur, truncated}, VerifyPolicy{MinBits: 128}); !errors.Is(err, ErrNoUsableMultihash) {
+		t.Errorf("expected ErrNoUsableMultihash, got %v", err)
+	}
+	if _, err := VerifyAny(data, []Multihash{unsupported}, VerifyPolicy{}); !errors.Is(err, ErrNoUsableMultihash) {
+		t.Errorf("expected ErrNoUsableMultihash, got %v", err)
+	}
+}
+
+func TestStrength(t *testing.T) {
+	for _, tc := range []struct {
+		code   uint64
+		length int
+		bits   int
+	}{
+		{SHA2_256, 32, 128},
+		{SHA2_256, 20, 80},
+		{SHA2_512, 64, 256},
+		{SHA3_256, 32, 128},
+		{BLAKE2B_MIN + 31, 32, 128},
+		{BLAKE3, 64, 128},
+		{MURMUR3X64_64, 8, 0},
+		{IDENTITY, 32, 0},
+		{POSEIDON_BLS12_381_A2_FC1, 32, 0},
+		{SHA2_256_TRUNC254_PADDED, 32, 0},
+	} {
+		s, ok := GetStrength(tc.code)
+		if !ok {
+			t.Errorf("%s: no strength registered", Codes[tc.code])
+			continue
+		}
+		if bits := s.EffectiveBits(tc.length); bits != tc.bits {
+			t.Errorf("%s/%d: expected %d bits, got %d", Codes[tc.code], tc.length, tc.bits, bits)
+		}
+	}
+}